@@ -0,0 +1,177 @@
+// backup_name_test.go 包含了备份文件名唯一性与顺序的测试用例。
+// 该文件验证同一秒内多次轮转不会覆盖已有备份，
+// 以及时钟回拨时备份文件仍按轮转顺序排列。
+
+package logrotatex
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// TestBackupName_SameSecond 测试同一秒内数百次轮转生成互不相同的备份文件, 且按轮转顺序排列
+func TestBackupName_SameSecond(t *testing.T) {
+	originalCurrentTime := currentTime
+	originalFake := fakeCurrentTime
+	defer func() {
+		currentTime = originalCurrentTime
+		fakeCurrentTime = originalFake
+	}()
+	currentTime = fakeTime
+	fakeCurrentTime = time.Date(2026, 10, 16, 9, 0, 0, 0, time.UTC)
+
+	dir := makeTempDir("TestBackupName_SameSecond", t)
+	defer func() { _ = os.RemoveAll(dir) }()
+
+	l := &LogRotateX{LogFilePath: logFile(dir)}
+	defer func() { _ = l.Close() }()
+
+	const rotations = 300
+	for i := range rotations {
+		_, err := fmt.Fprintf(l, "%d", i)
+		isNil(err, t)
+		isNil(l.Rotate(), t)
+	}
+
+	// 每次轮转都保留了独立的备份文件
+	fileCount(dir, rotations+1, t)
+	existsWithContent(filepath.Join(dir, "foobar_20261016090000.log"), []byte("0"), t)
+	existsWithContent(filepath.Join(dir, "foobar_20261016090000_1.log"), []byte("1"), t)
+	existsWithContent(filepath.Join(dir, fmt.Sprintf("foobar_20261016090000_%d.log", rotations-1)), []byte(fmt.Sprint(rotations-1)), t)
+
+	// 扫描结果按轮转顺序从新到旧排列, 没有被去重
+	files, err := l.oldLogFiles()
+	isNil(err, t)
+	equals(rotations, len(files), t)
+	for i, f := range files {
+		equals(rotations-1-i, f.seq, t)
+	}
+}
+
+// TestBackupName_RealClock 测试使用真实时钟快速轮转时不会丢失备份文件
+func TestBackupName_RealClock(t *testing.T) {
+	dir := makeTempDir("TestBackupName_RealClock", t)
+	defer func() { _ = os.RemoveAll(dir) }()
+
+	l := &LogRotateX{LogFilePath: logFile(dir), Namer: ISONamer}
+	defer func() { _ = l.Close() }()
+
+	const rotations = 500
+	for i := range rotations {
+		_, err := fmt.Fprintf(l, "%d", i)
+		isNil(err, t)
+		isNil(l.Rotate(), t)
+	}
+	fileCount(dir, rotations+1, t)
+
+	files, err := l.oldLogFiles()
+	isNil(err, t)
+	equals(rotations, len(files), t)
+
+	// 最新的备份文件包含最后一次写入的内容
+	existsWithContent(filepath.Join(dir, files[0].Name()), []byte(fmt.Sprint(rotations-1)), t)
+}
+
+// TestBackupName_ClockJumpBack 测试时钟回拨后的备份文件仍排在之前的备份之后, 并受清理规则正确处理
+func TestBackupName_ClockJumpBack(t *testing.T) {
+	originalCurrentTime := currentTime
+	originalFake := fakeCurrentTime
+	defer func() {
+		currentTime = originalCurrentTime
+		fakeCurrentTime = originalFake
+	}()
+	currentTime = fakeTime
+	fakeCurrentTime = time.Date(2026, 10, 16, 10, 0, 5, 0, time.UTC)
+
+	dir := makeTempDir("TestBackupName_ClockJumpBack", t)
+	defer func() { _ = os.RemoveAll(dir) }()
+
+	l := &LogRotateX{LogFilePath: logFile(dir), MaxFiles: 2}
+	defer func() { _ = l.Close() }()
+
+	_, err := l.Write([]byte("first"))
+	isNil(err, t)
+	isNil(l.Rotate(), t)
+
+	// 时钟回拨 1 小时
+	fakeCurrentTime = time.Date(2026, 10, 16, 9, 0, 0, 0, time.UTC)
+	_, err = l.Write([]byte("second"))
+	isNil(err, t)
+	isNil(l.Rotate(), t)
+
+	_, err = l.Write([]byte("third"))
+	isNil(err, t)
+	isNil(l.Rotate(), t)
+
+	// 回拨后的备份沿用上次的备份时间并递增序号, 最旧的 "first" 被清理
+	notExist(filepath.Join(dir, "foobar_20261016100005.log"), t)
+	existsWithContent(filepath.Join(dir, "foobar_20261016100005_1.log"), []byte("second"), t)
+	existsWithContent(filepath.Join(dir, "foobar_20261016100005_2.log"), []byte("third"), t)
+	fileCount(dir, 3, t)
+}
+
+// TestBackupName_SkipsCompressed 测试已压缩的同名备份不会被新的备份覆盖
+func TestBackupName_SkipsCompressed(t *testing.T) {
+	originalCurrentTime := currentTime
+	defer func() { currentTime = originalCurrentTime }()
+	currentTime = fakeTime
+
+	dir := makeTempDir("TestBackupName_SkipsCompressed", t)
+	defer func() { _ = os.RemoveAll(dir) }()
+
+	l := &LogRotateX{LogFilePath: logFile(dir)}
+	defer func() { _ = l.Close() }()
+
+	_, err := l.Write([]byte("data"))
+	isNil(err, t)
+
+	// 预置与本次备份同名的压缩文件
+	compressed := filepath.Join(dir, "foobar_"+fakeTime().UTC().Format(backupTimeFormat)+l.CompressType.String())
+	isNil(os.WriteFile(compressed, []byte("compressed"), 0600), t)
+
+	isNil(l.Rotate(), t)
+
+	existsWithContent(compressed, []byte("compressed"), t)
+	existsWithContent(filepath.Join(dir, "foobar_"+fakeTime().UTC().Format(backupTimeFormat)+"_1.log"), []byte("data"), t)
+}
+
+// TestBackupName_SeqNotReused 测试同一时间戳内清理删除的序号不会被复用, 新的备份不会被当作最旧的备份立即删除
+func TestBackupName_SeqNotReused(t *testing.T) {
+	originalCurrentTime := currentTime
+	originalFake := fakeCurrentTime
+	defer func() {
+		currentTime = originalCurrentTime
+		fakeCurrentTime = originalFake
+	}()
+	currentTime = fakeTime
+	fakeCurrentTime = time.Date(2026, 10, 16, 9, 0, 0, 0, time.UTC)
+
+	dir := makeTempDir("TestBackupName_SeqNotReused", t)
+	defer func() { _ = os.RemoveAll(dir) }()
+
+	l := &LogRotateX{LogFilePath: logFile(dir), MaxFiles: 2}
+	defer func() { _ = l.Close() }()
+
+	for i := range 5 {
+		_, err := fmt.Fprintf(l, "w%d", i)
+		isNil(err, t)
+		isNil(l.Rotate(), t)
+	}
+
+	// 保留的是最新的两次写入
+	fileCount(dir, 3, t)
+	existsWithContent(filepath.Join(dir, "foobar_20261016090000_3.log"), []byte("w3"), t)
+	existsWithContent(filepath.Join(dir, "foobar_20261016090000_4.log"), []byte("w4"), t)
+
+	// 重启后首次轮转从磁盘上已有的最大序号继续
+	isNil(l.Close(), t)
+	l = &LogRotateX{LogFilePath: logFile(dir), MaxFiles: 2}
+	_, err := l.Write([]byte("w5"))
+	isNil(err, t)
+	isNil(l.Rotate(), t)
+	existsWithContent(filepath.Join(dir, "foobar_20261016090000_5.log"), []byte("w5"), t)
+	existsWithContent(filepath.Join(dir, "foobar_20261016090000_4.log"), []byte("w4"), t)
+}
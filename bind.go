@@ -0,0 +1,202 @@
+// bind.go 实现了从命令行参数和环境变量读取配置的功能。
+// BindFlags 和 BindBufFlags 为 LogRotateX 和 BufCfg 的每个可配置字段注册一个命令行参数
+// (名称为前缀加字段名的连字符形式，如 --log.compress-type)，FromEnv 从带前缀的环境变量读取配置 (如 APP_LOG_MAXSIZE)。
+// 可配置的字段和值的格式与配置文件 (config.go) 相同，未设置的字段保持 NewLogRotateX 和 DefBufCfg 的默认值。
+
+package logrotatex
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"reflect"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// configUsage 是命令行参数的说明, 键为字段名
+var configUsage = map[string]string{
+	"LogFilePath":            "log file path, may contain strftime placeholders such as %Y%m%d",
+	"Async":                  "clean up old log files in a background goroutine",
+	"MaxSize":                "maximum size of the log file in megabytes before rotation",
+	"MaxBytes":               "maximum size of the log file before rotation, e.g. 512KB (overrides max size)",
+	"MaxTotalSize":           "maximum total size of backup files, e.g. 1GiB",
+	"MinFreeSpace":           "remove old backups while free disk space is below this size, e.g. 500MB",
+	"MinFreePercent":         "remove old backups while free disk space is below this percentage",
+	"FreeSpaceCheckInterval": "minimum interval between free space checks",
+	"MaxAge":                 "maximum age of backup files, in days or as a duration such as 7d or 2w",
+	"MaxFiles":               "maximum number of backup files to keep",
+	"LocalTime":              "use local time instead of UTC in backup file names",
+	"Compress":               "compress backup files",
+	"DateDirLayout":          "store backup files in YYYY-MM-DD directories",
+	"RotateByDay":            "rotate the log file when the day changes",
+	"NumberedBackups":        "use logrotate style numbered backup names (name.ext.1, name.ext.2 ...)",
+	"LinkName":               "symlink pointing to the current log file",
+	"BackupLinkName":         "symlink pointing to the newest backup file",
+	"RotateSchedule":         "rotation schedule, e.g. 1h, @daily or a cron expression",
+	"BackgroundRotate":       "rotate at schedule boundaries even without writes",
+	"CleanupInterval":        "interval of background cleanup, 0 cleans up only after rotation",
+	"CompressType":           "compression type: zip, tar, tgz, tar.gz, gz, bz2, bzip2 or zlib",
+	"MultiProcess":           "coordinate rotation between processes sharing the log file",
+	"WatchFile":              "reopen the log file when it is moved, truncated or removed externally",
+	"WatchInterval":          "minimum interval between log file checks in watch mode",
+	"RotateMode":             "rotation mode: rename or copytruncate",
+	"DiskFullPolicy":         "disk full policy: fail, evict, fallback or drop",
+	"BufCfg.MaxBufferSize":   "maximum buffer size before flushing, e.g. 256KB",
+	"BufCfg.FlushInterval":   "interval of background flushes",
+}
+
+// BindFlags 为 LogRotateX 的每个可配置字段在 fs 中注册一个命令行参数, 返回的实例在 fs.Parse 后生效。
+// 参数名为 prefix + "." + 字段名的连字符形式, 如 prefix 为 "log" 时 MaxSize 对应 --log.max-size,
+// CompressType 对应 --log.compress-type; prefix 为空时不加前缀。值的格式与配置文件相同 (见 FromConfig)。
+//
+// 未指定的参数保持 NewLogRotateX 的默认值。解析时检查每个值的格式和 CompressType 是否为 comprx 支持的类型,
+// 失败时由 fs 按其 ErrorHandling 处理; 字段组合在 fs.Parse 后通过 Validate 检查。
+//
+// 参数:
+//   - fs: 命令行参数集合, 如 flag.CommandLine
+//   - prefix: 参数名前缀
+//
+// 返回值:
+//   - *LogRotateX: 绑定了命令行参数的实例
+func BindFlags(fs *flag.FlagSet, prefix string) *LogRotateX {
+	l := NewLogRotateX("")
+	bindConfigFlags(fs, prefix, configFields, l, nil)
+	return l
+}
+
+// BindBufFlags 为 BufCfg 的字段在 fs 中注册命令行参数, 参数名为 prefix + ".buffer." + 字段名的连字符形式,
+// 如 --log.buffer.max-buffer-size、--log.buffer.flush-interval。未指定的参数保持 DefBufCfg 的默认值。
+//
+// 参数:
+//   - fs: 命令行参数集合
+//   - prefix: 参数名前缀, 通常与 BindFlags 相同
+//
+// 返回值:
+//   - *BufCfg: 绑定了命令行参数的配置
+func BindBufFlags(fs *flag.FlagSet, prefix string) *BufCfg {
+	buf := DefBufCfg()
+	bindConfigFlags(fs, joinFlagName(prefix, bufferSection), bufConfigFields, nil, buf)
+	return buf
+}
+
+// bindConfigFlags 按字段名顺序为 fields 中的每个字段注册命令行参数
+func bindConfigFlags(fs *flag.FlagSet, prefix string, fields map[string]configField, l *LogRotateX, buf *BufCfg) {
+	sorted := make([]configField, 0, len(fields))
+	for _, f := range fields {
+		sorted = append(sorted, f)
+	}
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].name < sorted[j].name })
+
+	for _, f := range sorted {
+		name := joinFlagName(prefix, kebabCase(strings.TrimPrefix(f.name, "BufCfg.")))
+		fs.Var(&configFlag{l: l, buf: buf, field: f}, name, configUsage[f.name])
+	}
+}
+
+// joinFlagName 以点号连接参数名前缀和名称, 前缀为空时只返回名称
+func joinFlagName(prefix, name string) string {
+	if prefix == "" {
+		return name
+	}
+	return prefix + "." + name
+}
+
+// kebabCase 将字段名转换为连字符形式, 如 MaxFileSize 转换为 max-file-size
+func kebabCase(name string) string {
+	var b strings.Builder
+	for i, r := range name {
+		if unicode.IsUpper(r) {
+			if i > 0 && !unicode.IsUpper(rune(name[i-1])) {
+				b.WriteByte('-')
+			}
+			r = unicode.ToLower(r)
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+// configFlag 是绑定到一个可配置字段的命令行参数, 实现 flag.Value
+type configFlag struct {
+	l     *LogRotateX // l 是绑定的实例, 绑定 BufCfg 字段时为 nil
+	buf   *BufCfg     // buf 是绑定的缓冲写入器配置, 绑定 LogRotateX 字段时为 nil
+	field configField // field 是绑定的字段
+}
+
+// String 返回字段的当前值, 零值返回空字符串 (flag 包以此判断是否显示默认值)
+func (f *configFlag) String() string {
+	v := f.value()
+	if !v.IsValid() || v.IsZero() {
+		return ""
+	}
+	return fmt.Sprint(v.Interface())
+}
+
+// Set 解析并设置字段的值
+func (f *configFlag) Set(s string) error {
+	return f.field.set(f.l, f.buf, strings.TrimSpace(s))
+}
+
+// IsBoolFlag 使布尔字段的参数可以省略值, 如 --log.compress
+func (f *configFlag) IsBoolFlag() bool {
+	v := f.value()
+	return v.IsValid() && v.Kind() == reflect.Bool
+}
+
+// value 返回绑定的字段, flag 包创建的零值参数返回无效值
+func (f *configFlag) value() reflect.Value {
+	switch {
+	case f.l != nil:
+		return reflect.ValueOf(f.l).Elem().FieldByName(f.field.name)
+	case f.buf != nil:
+		return reflect.ValueOf(f.buf).Elem().FieldByName(strings.TrimPrefix(f.field.name, "BufCfg."))
+	default:
+		return reflect.Value{}
+	}
+}
+
+// FromEnv 从环境变量创建 LogRotateX 和 BufCfg。
+// 变量名为 prefix + "_" + 字段名, 字段名不区分大小写并忽略下划线, 如 prefix 为 "APP_LOG" 时
+// APP_LOG_MAXSIZE 和 APP_LOG_MAX_SIZE 都对应 MaxSize; BufCfg 的字段使用 prefix + "_BUFFER_",
+// 如 APP_LOG_BUFFER_FLUSHINTERVAL。值的格式与配置文件相同 (见 FromConfig), 不对应任何字段的变量被忽略。
+//
+// 参数:
+//   - prefix: 环境变量名前缀, 为空时直接使用字段名
+//
+// 返回值:
+//   - *LogRotateX: 配置好的实例, 未设置的字段与 NewLogRotateX 的默认值相同
+//   - *BufCfg: 缓冲写入器配置, 没有设置 BUFFER 变量时为 nil
+//   - error: 值无法解析或校验失败时返回 ConfigErrors
+func FromEnv(prefix string) (*LogRotateX, *BufCfg, error) {
+	var values []configValue
+	for _, kv := range os.Environ() {
+		key, value, _ := strings.Cut(kv, "=")
+		name := key
+		if prefix != "" {
+			var ok bool
+			if name, ok = strings.CutPrefix(key, prefix+"_"); !ok {
+				continue
+			}
+		}
+
+		name = normalizeConfigKey(name)
+		if _, ok := configFields[name]; ok {
+			values = append(values, configValue{key: name, value: value})
+		} else if field, ok := strings.CutPrefix(name, bufferSection); ok {
+			if _, ok := bufConfigFields[field]; ok {
+				values = append(values, configValue{section: bufferSection, key: field, value: value})
+			}
+		}
+	}
+
+	// 按名称排序, 使错误的顺序固定
+	sort.Slice(values, func(i, j int) bool {
+		if values[i].section != values[j].section {
+			return values[i].section < values[j].section
+		}
+		return values[i].key < values[j].key
+	})
+	return newFromConfigValues(values)
+}
@@ -0,0 +1,110 @@
+// bind_test.go 包含了命令行参数和环境变量绑定 (BindFlags/FromEnv) 的测试用例。
+// 该文件验证未指定时与 NewLogRotateX 一致，参数名和变量名的写法，
+// 以及无效的压缩类型在解析时被拒绝。
+
+package logrotatex
+
+import (
+	"errors"
+	"flag"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"gitee.com/MM-Q/comprx"
+)
+
+// TestBindFlags 测试命令行参数设置对应的字段
+func TestBindFlags(t *testing.T) {
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+	l := BindFlags(fs, "log")
+	buf := BindBufFlags(fs, "log")
+
+	// 未指定参数时与默认值一致
+	isNil(fs.Parse(nil), t)
+	equals(NewLogRotateX(""), l, t)
+	equals(DefBufCfg(), buf, t)
+
+	isNil(fs.Parse([]string{
+		"--log.log-file-path=logs/app.log",
+		"--log.max-size", "100",
+		"--log.max-age=2w",
+		"--log.compress",
+		"--log.compress-type=gz",
+		"--log.rotate-by-day=false",
+		"--log.max-total-size=1GiB",
+		"--log.buffer.flush-interval=3s",
+	}), t)
+
+	want := NewLogRotateX("logs/app.log")
+	want.MaxSize = 100
+	want.MaxAge = 14
+	want.Compress = true
+	want.CompressType = comprx.CompressTypeGz
+	want.RotateByDay = false
+	want.MaxTotalSize = ByteSize(1 << 30)
+	equals(want, l, t)
+	equals(3*time.Second, buf.FlushInterval, t)
+
+	// 默认值显示在帮助信息中
+	equals(".zip", fs.Lookup("log.compress-type").DefValue, t)
+	equals("", fs.Lookup("log.max-files").DefValue, t)
+
+	// 无效的压缩类型在解析时被拒绝
+	err := fs.Parse([]string{"--log.compress-type=rar"})
+	if err == nil || !strings.Contains(err.Error(), "log.compress-type") {
+		t.Fatalf("期望压缩类型错误, 实际: %v", err)
+	}
+	equals(comprx.CompressTypeGz, l.CompressType, t)
+
+	// 没有前缀
+	fs = flag.NewFlagSet("test", flag.ContinueOnError)
+	l = BindFlags(fs, "")
+	isNil(fs.Parse([]string{"-max-files=3"}), t)
+	equals(3, l.MaxFiles, t)
+}
+
+// TestFromEnv 测试环境变量设置对应的字段, 变量名忽略大小写和下划线
+func TestFromEnv(t *testing.T) {
+	t.Setenv("APP_LOG_MAXSIZE", "100")
+	t.Setenv("APP_LOG_MAX_FILES", "5")
+	t.Setenv("APP_LOG_COMPRESS_TYPE", "tar.gz")
+	t.Setenv("APP_LOG_BUFFER_MAXBUFFERSIZE", "64KB")
+	t.Setenv("APP_LOG_LEVEL", "debug")
+	t.Setenv("MAXAGE", "7")
+
+	l, buf, err := FromEnv("APP_LOG")
+	isNil(err, t)
+	want := NewLogRotateX("")
+	want.MaxSize = 100
+	want.MaxFiles = 5
+	want.CompressType = comprx.CompressTypeTarGz
+	equals(want, l, t)
+	equals(&BufCfg{MaxBufferSize: 64 * 1024, FlushInterval: DefBufCfg().FlushInterval}, buf, t)
+
+	// 没有设置 BUFFER 变量时 BufCfg 为 nil
+	_, buf, err = FromEnv("OTHER_LOG")
+	isNil(err, t)
+	equals((*BufCfg)(nil), buf, t)
+
+	t.Setenv("APP_LOG_COMPRESSTYPE", "rar")
+	_, _, err = FromEnv("APP_LOG")
+	var errs ConfigErrors
+	if !errors.As(err, &errs) || errs[0].Field != "CompressType" {
+		t.Fatalf("期望压缩类型错误, 实际: %v", err)
+	}
+}
+
+// TestKebabCase 测试字段名到参数名的转换
+func TestKebabCase(t *testing.T) {
+	for name, want := range map[string]string{
+		"MaxSize":                "max-size",
+		"LogFilePath":            "log-file-path",
+		"FreeSpaceCheckInterval": "free-space-check-interval",
+		"Async":                  "async",
+	} {
+		equals(want, kebabCase(name), t)
+	}
+}
@@ -196,7 +196,7 @@ func TestBoundaryConditions(t *testing.T) {
 			}
 		}()
 
-		// 写入3次，触发2次轮转
+		// 写入4次，触发3次轮转
 		// 第一次写入，不触发轮转
 		b1 := make([]byte, megabyte-1)
 		n, err := l.Write(b1)
@@ -210,12 +210,12 @@ func TestBoundaryConditions(t *testing.T) {
 		isNil(err, t)
 		equals(len(b2), n, t)
 
-		// 第三次写入，不触发轮转
+		// 第三次写入，总大小超过1MB，触发第二次轮转
 		n, err = l.Write(b1)
 		isNil(err, t)
 		equals(len(b1), n, t)
 
-		// 第四次写入，触发第二次轮转
+		// 第四次写入，触发第三次轮转
 		newFakeTime()
 		n, err = l.Write(b2)
 		isNil(err, t)
@@ -242,9 +242,9 @@ func TestBoundaryConditions(t *testing.T) {
 			}
 		}
 
-		// 应该有2个备份文件
-		if backupCount != 2 {
-			t.Errorf("期望2个备份文件，实际找到%d个", backupCount)
+		// 应该有3个备份文件 (同一秒内的轮转不会覆盖之前的备份)
+		if backupCount != 3 {
+			t.Errorf("期望3个备份文件，实际找到%d个", backupCount)
 		}
 	})
 
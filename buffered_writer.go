@@ -50,12 +50,19 @@ type BufferedWriter struct {
 	// 状态跟踪
 	lastFlush time.Time   // 上次刷新时间
 	closed    atomic.Bool // 是否已关闭
+
+	// 刷新统计
+	flushes     atomic.Int64 // 成功刷新 (写出非空缓冲区) 的次数
+	flushErrors atomic.Int64 // 刷新失败的次数
+
+	errorHandler func(error) // 定时刷新失败时的错误处理函数, 为 nil 时写入标准错误输出
 }
 
 // BufCfg 缓冲写入器配置
 type BufCfg struct {
 	MaxBufferSize int           // 最大缓冲区大小, 默认256KB
 	FlushInterval time.Duration // 刷新间隔, 默认1秒(最小500ms)
+	ErrorHandler  func(error)   // 定时刷新失败时的错误处理函数 (错误类型为 *OpError), 默认写入标准错误输出
 }
 
 // DefBufCfg 默认缓冲写入器配置
@@ -176,7 +183,7 @@ func (bw *BufferedWriter) initDefaults() error {
 				// 防止定时器协程panic导致程序崩溃
 				defer func() {
 					if r := recover(); r != nil {
-						fmt.Printf("BufferedWriter flush ticker panic: %v stack: %s\n", r, debug.Stack())
+						bw.reportError(OpFlush, fmt.Errorf("flush ticker panic: %v stack: %s", r, debug.Stack()))
 					}
 				}()
 
@@ -187,9 +194,9 @@ func (bw *BufferedWriter) initDefaults() error {
 						if bw.closed.Load() {
 							return
 						}
-						// 刷新失败时打印错误日志
+						// 刷新失败时报告错误
 						if err := bw.Flush(); err != nil {
-							fmt.Printf("ticker flush failed: %v\n", err)
+							bw.reportError(OpFlush, err)
 						}
 					case <-bw.closeChan:
 						// 收到关闭信号，立即退出
@@ -225,6 +232,7 @@ func NewBufferedWriter(wc io.WriteCloser, config *BufCfg) *BufferedWriter {
 		wc:            wc,                   // 底层写入+关闭器 (必需)
 		maxBufferSize: config.MaxBufferSize, // 最大缓冲区大小 (字节)
 		flushInterval: config.FlushInterval, // 刷新间隔
+		errorHandler:  config.ErrorHandler,  // 错误处理函数
 	}
 
 	// 初始化默认值
@@ -308,11 +316,13 @@ func (bw *BufferedWriter) flushLocked() error {
 	// 使用 bytes.Buffer.WriteTo 来处理部分写入与循环写入
 	if _, err := bw.buffer.WriteTo(bw.wc); err != nil {
 		// 出错时, WriteTo 已消耗掉已写出的前缀, 剩余数据仍保留在缓冲区
+		bw.flushErrors.Add(1)
 		return fmt.Errorf("flush failed: %w", err)
 	}
 
 	// 写入成功后, 缓冲区已被消费为空, 无需Reset, WriteTo会自动清空缓冲区
 	bw.lastFlush = time.Now() // 更新刷新时间
+	bw.flushes.Add(1)
 	return nil
 }
 
@@ -0,0 +1,172 @@
+// byte_size.go 实现了logrotatex包的字节大小类型。
+// ByteSize 支持从 "512KB"、"1.5GiB" 等可读字符串解析，
+// 可以直接用于 JSON/YAML 配置中的大小字段 (如 MaxBytes)。
+
+package logrotatex
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// ByteSize 是以字节为单位的大小, 可以从数字或带单位的字符串解析。
+//
+// 支持的单位 (不区分大小写, 均按 1024 进制计算, 与 MaxSize 的 MB 一致):
+//   - B
+//   - K, KB, KiB
+//   - M, MB, MiB
+//   - G, GB, GiB
+//   - T, TB, TiB
+//
+// 例如 "512KB" = 524288, "1.5GiB" = 1610612736, 不带单位的数字表示字节数。
+type ByteSize int64
+
+// 常用的字节大小
+const (
+	Byte     ByteSize = 1
+	KiloByte          = 1024 * Byte
+	MegaByte          = 1024 * KiloByte
+	GigaByte          = 1024 * MegaByte
+	TeraByte          = 1024 * GigaByte
+)
+
+// byteSizeUnits 是单位后缀到字节数的映射 (小写)
+var byteSizeUnits = map[string]ByteSize{
+	"":    Byte,
+	"b":   Byte,
+	"k":   KiloByte,
+	"kb":  KiloByte,
+	"kib": KiloByte,
+	"m":   MegaByte,
+	"mb":  MegaByte,
+	"mib": MegaByte,
+	"g":   GigaByte,
+	"gb":  GigaByte,
+	"gib": GigaByte,
+	"t":   TeraByte,
+	"tb":  TeraByte,
+	"tib": TeraByte,
+}
+
+// ParseByteSize 解析带单位的字节大小字符串
+//
+// 参数:
+//   - s: 字节大小字符串, 如 "512KB", "1.5GiB", "1048576"
+//
+// 返回值:
+//   - ByteSize: 字节大小
+//   - error: 格式无效、为负数或超出范围时返回错误
+func ParseByteSize(s string) (ByteSize, error) {
+	str := strings.TrimSpace(s)
+	if str == "" {
+		return 0, fmt.Errorf("invalid byte size %q: empty", s)
+	}
+
+	// 拆分数字部分和单位部分
+	i := 0
+	for i < len(str) && (str[i] >= '0' && str[i] <= '9' || str[i] == '.') {
+		i++
+	}
+	num, unit := str[:i], strings.ToLower(strings.TrimSpace(str[i:]))
+
+	multiplier, ok := byteSizeUnits[unit]
+	if !ok {
+		return 0, fmt.Errorf("invalid byte size %q: unknown unit %q", s, str[i:])
+	}
+
+	// 整数直接计算, 避免浮点误差
+	if n, err := strconv.ParseInt(num, 10, 64); err == nil {
+		if n > math.MaxInt64/int64(multiplier) {
+			return 0, fmt.Errorf("invalid byte size %q: out of range", s)
+		}
+		return ByteSize(n) * multiplier, nil
+	}
+
+	f, err := strconv.ParseFloat(num, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid byte size %q: %w", s, err)
+	}
+	v := f * float64(multiplier)
+	if v >= math.MaxInt64 {
+		return 0, fmt.Errorf("invalid byte size %q: out of range", s)
+	}
+	return ByteSize(v), nil
+}
+
+// String 返回可读的字节大小, 能整除时使用最大的单位, 如 "512KB"、"1536MB"
+func (b ByteSize) String() string {
+	for _, u := range []struct {
+		size ByteSize
+		name string
+	}{
+		{TeraByte, "TB"},
+		{GigaByte, "GB"},
+		{MegaByte, "MB"},
+		{KiloByte, "KB"},
+	} {
+		if b != 0 && b%u.size == 0 {
+			return strconv.FormatInt(int64(b/u.size), 10) + u.name
+		}
+	}
+	return strconv.FormatInt(int64(b), 10) + "B"
+}
+
+// MarshalText 实现 encoding.TextMarshaler 接口, 输出可读的字节大小
+func (b ByteSize) MarshalText() ([]byte, error) {
+	return []byte(b.String()), nil
+}
+
+// UnmarshalText 实现 encoding.TextUnmarshaler 接口, 用于 YAML 等文本配置
+func (b *ByteSize) UnmarshalText(text []byte) error {
+	v, err := ParseByteSize(string(text))
+	if err != nil {
+		return err
+	}
+	*b = v
+	return nil
+}
+
+// UnmarshalJSON 实现 json.Unmarshaler 接口, 同时支持数字 (字节数) 和字符串, 负数和不是整字节的小数返回错误
+func (b *ByteSize) UnmarshalJSON(data []byte) error {
+	data = bytes.TrimSpace(data)
+	if bytes.Equal(data, []byte("null")) {
+		return nil
+	}
+
+	if len(data) > 0 && data[0] == '"' {
+		var s string
+		if err := json.Unmarshal(data, &s); err != nil {
+			return err
+		}
+		return b.UnmarshalText([]byte(s))
+	}
+
+	// 数字按字节数解析, 与字符串一样拒绝负数, 小数只接受整字节 (如 1024.0)
+	num := string(data)
+	if n, err := strconv.ParseInt(num, 10, 64); err == nil {
+		if n < 0 {
+			return fmt.Errorf("invalid byte size %q: negative", num)
+		}
+		*b = ByteSize(n)
+		return nil
+	}
+
+	f, err := strconv.ParseFloat(num, 64)
+	if err != nil {
+		return fmt.Errorf("invalid byte size %q: %w", num, err)
+	}
+	switch {
+	case f < 0:
+		return fmt.Errorf("invalid byte size %q: negative", num)
+	case f != math.Trunc(f):
+		return fmt.Errorf("invalid byte size %q: not a whole number of bytes", num)
+	case f >= math.MaxInt64:
+		return fmt.Errorf("invalid byte size %q: out of range", num)
+	}
+	*b = ByteSize(f)
+	return nil
+}
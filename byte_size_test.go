@@ -0,0 +1,92 @@
+// byte_size_test.go 包含了字节大小 (ByteSize/MaxBytes) 的测试用例。
+// 该文件验证带单位字符串的解析、JSON 数字与字符串的反序列化，
+// 以及 MaxBytes 优先于 MaxSize 生效。
+
+package logrotatex
+
+import (
+	"encoding/json"
+	"os"
+	"strings"
+	"testing"
+)
+
+// TestParseByteSize 测试带单位的字节大小字符串解析
+func TestParseByteSize(t *testing.T) {
+	tests := []struct {
+		in   string
+		want ByteSize
+	}{
+		{"0", 0},
+		{"1048576", 1048576},
+		{"100B", 100},
+		{"512KB", 512 * 1024},
+		{"512kb", 512 * 1024},
+		{"256 KiB", 256 * 1024},
+		{"10M", 10 * 1024 * 1024},
+		{"1.5GiB", 1610612736},
+		{"2TB", 2 * 1024 * 1024 * 1024 * 1024},
+	}
+	for _, tt := range tests {
+		got, err := ParseByteSize(tt.in)
+		isNil(err, t)
+		equals(tt.want, got, t)
+	}
+
+	for _, in := range []string{"", "KB", "-1KB", "10XB", "1.2.3MB", "99999999999TB"} {
+		if _, err := ParseByteSize(in); err == nil {
+			t.Errorf("期望 %q 解析失败", in)
+		}
+	}
+}
+
+// TestByteSize_JSON 测试 JSON 配置中使用数字或字符串设置 MaxBytes
+func TestByteSize_JSON(t *testing.T) {
+	var l LogRotateX
+	isNil(json.Unmarshal([]byte(`{"maxbytes": "256KB"}`), &l), t)
+	equals(ByteSize(256*1024), l.MaxBytes, t)
+
+	isNil(json.Unmarshal([]byte(`{"maxbytes": 4096}`), &l), t)
+	equals(ByteSize(4096), l.MaxBytes, t)
+
+	isNil(json.Unmarshal([]byte(`{"maxbytes": 1e3}`), &l), t)
+	equals(ByteSize(1000), l.MaxBytes, t)
+
+	// 数字与字符串一样校验: 负数和不是整字节的小数返回相同格式的错误, 原值不变
+	for _, v := range []string{`"lots"`, `"-1"`, `-1`, `1.5`, `-0.5`, `1e30`} {
+		err := json.Unmarshal([]byte(`{"maxbytes": `+v+`}`), &l)
+		if err == nil || !strings.Contains(err.Error(), "invalid byte size") {
+			t.Fatalf("期望 %s 返回无效大小错误, 实际: %v", v, err)
+		}
+		equals(ByteSize(1000), l.MaxBytes, t)
+	}
+
+	data, err := json.Marshal(ByteSize(1536 * 1024 * 1024))
+	isNil(err, t)
+	equals(`"1536MB"`, string(data), t)
+}
+
+// TestMaxBytes_Rotate 测试 MaxBytes 优先于 MaxSize 触发轮转
+func TestMaxBytes_Rotate(t *testing.T) {
+	originalCurrentTime := currentTime
+	defer func() { currentTime = originalCurrentTime }()
+	currentTime = fakeTime
+
+	dir := makeTempDir("TestMaxBytes_Rotate", t)
+	defer func() { _ = os.RemoveAll(dir) }()
+
+	l := &LogRotateX{LogFilePath: logFile(dir), MaxSize: 10, MaxBytes: 10}
+	defer func() { _ = l.Close() }()
+	equals(int64(10), l.max(), t)
+
+	_, err := l.Write([]byte("boo!"))
+	isNil(err, t)
+	fileCount(dir, 1, t)
+
+	newFakeTime()
+	_, err = l.Write([]byte("foooooo!"))
+	isNil(err, t)
+	existsWithContent(logFile(dir), []byte("foooooo!"), t)
+	existsWithContent(backupFile(dir), []byte("boo!"), t)
+	fileCount(dir, 2, t)
+}
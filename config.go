@@ -0,0 +1,568 @@
+// config.go 实现了logrotatex包的配置文件加载功能。
+// LoadConfig 和 FromConfig 支持 JSON、YAML 和 TOML 三种格式，配置项名称与 LogRotateX 的 json 标签一致
+// (不区分大小写，忽略下划线和连字符)，BufferedWriter 的配置放在 buffer 小节中。
+// YAML 和 TOML 分别由 gopkg.in/yaml.v3 和 github.com/BurntSushi/toml 解析，配置只使用键值对和一层小节。
+//
+// 示例 (YAML):
+//
+//	logfilepath: logs/app.log
+//	maxsize: 100
+//	maxage: 7d
+//	compress: true
+//	compress_type: gz
+//	buffer:
+//	  maxbuffersize: 256KB
+//	  flushinterval: 1s
+
+package logrotatex
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"gitee.com/MM-Q/comprx"
+	"github.com/BurntSushi/toml"
+	"gopkg.in/yaml.v3"
+)
+
+// ConfigFormat 是配置文件的格式
+type ConfigFormat string
+
+const (
+	// ConfigJSON JSON 格式
+	ConfigJSON ConfigFormat = "json"
+
+	// ConfigYAML YAML 格式 (键值对和一层嵌套的小节)
+	ConfigYAML ConfigFormat = "yaml"
+
+	// ConfigTOML TOML 格式 (键值对和 [buffer] 表)
+	ConfigTOML ConfigFormat = "toml"
+)
+
+// bufferSection 是 BufCfg 配置所在的小节名称
+const bufferSection = "buffer"
+
+// errUnknownField 是未知配置项的错误原因
+var errUnknownField = errors.New("unknown field")
+
+// FieldError 是单个配置项的错误
+type FieldError struct {
+	Field string // Field 是配置项对应的字段名, 如 "MaxAge"、"BufCfg.FlushInterval", 未知配置项为配置中的原始名称
+	Value string // Value 是配置项的值, 校验错误时为字段的当前值
+	Err   error  // Err 是具体原因
+}
+
+// Error 实现 error 接口
+func (e *FieldError) Error() string {
+	if e.Value == "" {
+		return e.Field + ": " + e.Err.Error()
+	}
+	return e.Field + " " + strconv.Quote(e.Value) + ": " + e.Err.Error()
+}
+
+// Unwrap 返回具体原因
+func (e *FieldError) Unwrap() error {
+	return e.Err
+}
+
+// ConfigErrors 是一次加载或校验中发现的所有配置错误, 可以通过 errors.As 逐个获取 *FieldError
+type ConfigErrors []*FieldError
+
+// Error 实现 error 接口, 按顺序列出所有错误
+func (e ConfigErrors) Error() string {
+	if len(e) == 1 {
+		return "invalid config: " + e[0].Error()
+	}
+	var msg strings.Builder
+	msg.WriteString("invalid config:")
+	for _, err := range e {
+		msg.WriteString("\n  - " + err.Error())
+	}
+	return msg.String()
+}
+
+// Unwrap 返回所有错误, 供 errors.Is 和 errors.As 使用
+func (e ConfigErrors) Unwrap() []error {
+	errs := make([]error, len(e))
+	for i, err := range e {
+		errs[i] = err
+	}
+	return errs
+}
+
+// errOrNil 没有错误时返回 nil, 避免返回非 nil 的空切片
+func (e ConfigErrors) errOrNil() error {
+	if len(e) == 0 {
+		return nil
+	}
+	return e
+}
+
+// LoadConfig 从配置文件创建 LogRotateX, 格式由扩展名决定 (.json, .yaml/.yml, .toml)。
+//
+// 参数:
+//   - path: 配置文件路径
+//
+// 返回值:
+//   - *LogRotateX: 配置好的实例, 未配置的字段与 NewLogRotateX 的默认值相同
+//   - *BufCfg: buffer 小节的配置, 没有 buffer 小节时为 nil
+//   - error: 读取、解析或校验失败时返回错误, 配置项错误的类型为 ConfigErrors
+func LoadConfig(path string) (*LogRotateX, *BufCfg, error) {
+	var format ConfigFormat
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".json":
+		format = ConfigJSON
+	case ".yaml", ".yml":
+		format = ConfigYAML
+	case ".toml":
+		format = ConfigTOML
+	default:
+		return nil, nil, fmt.Errorf("unsupported config file extension %q", filepath.Ext(path))
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to open config file: %w", err)
+	}
+	defer func() { _ = f.Close() }()
+
+	return FromConfig(f, format)
+}
+
+// FromConfig 从 r 中读取指定格式的配置并创建 LogRotateX。
+// 所有配置项错误 (未知配置项、无法解析的值、Validate 发现的问题) 合并为一个 ConfigErrors 返回。
+//
+// 值的格式:
+//   - MaxAge: 天数, 或带单位的时长, 如 "7d"、"2w"、"168h" (必须是整天)
+//   - 字节大小 (MaxBytes, MaxTotalSize, MinFreeSpace, buffer.MaxBufferSize): 字节数或 "256KB"、"1.5GiB"
+//   - 时长 (CleanupInterval, WatchInterval 等): "30s"、"5m"、"1d"
+//   - CompressType: "zip"、"gz"、".tar.gz" 等 comprx 支持的类型
+//
+// 参数:
+//   - r: 配置内容
+//   - format: 配置格式
+//
+// 返回值:
+//   - *LogRotateX: 配置好的实例, 未配置的字段与 NewLogRotateX 的默认值相同
+//   - *BufCfg: buffer 小节的配置, 没有 buffer 小节时为 nil
+//   - error: 解析或校验失败时返回错误
+func FromConfig(r io.Reader, format ConfigFormat) (*LogRotateX, *BufCfg, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to read config: %w", err)
+	}
+
+	var values []configValue
+	switch ConfigFormat(strings.ToLower(string(format))) {
+	case ConfigJSON:
+		values, err = parseJSONConfig(data)
+	case ConfigYAML:
+		values, err = parseYAMLConfig(data)
+	case ConfigTOML:
+		values, err = parseTOMLConfig(data)
+	default:
+		return nil, nil, fmt.Errorf("unsupported config format %q", format)
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+	return newFromConfigValues(values)
+}
+
+// newFromConfigValues 从 NewLogRotateX 的默认值开始应用配置项并校验, 第一次出现 buffer 小节的配置项时创建 BufCfg
+//
+// 返回值:
+//   - error: 所有配置项错误合并为一个 ConfigErrors
+func newFromConfigValues(values []configValue) (*LogRotateX, *BufCfg, error) {
+	l := NewLogRotateX("")
+	var buf *BufCfg
+	var errs ConfigErrors
+	for _, v := range values {
+		if v.section == bufferSection && buf == nil {
+			buf = DefBufCfg()
+		}
+		if err := applyConfigValue(l, buf, v); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	// 值都能解析后再校验组合
+	if len(errs) == 0 {
+		errs = append(errs, l.validate()...)
+		if buf != nil {
+			errs = append(errs, buf.validate()...)
+		}
+	}
+	if len(errs) > 0 {
+		return nil, nil, errs
+	}
+	return l, buf, nil
+}
+
+// configValue 是从配置文件中解析出的一个配置项
+type configValue struct {
+	section string // section 是所在的小节, 顶层配置项为空
+	key     string // key 是配置项名称 (原始写法)
+	value   string // value 是配置项的值 (已去掉引号)
+}
+
+// configField 是一个可配置的字段
+type configField struct {
+	name string                                         // name 是字段名
+	set  func(l *LogRotateX, b *BufCfg, v string) error // set 解析并设置字段的值
+}
+
+// configFields 是 LogRotateX 的可配置字段, 键为规范化的配置项名称 (见 normalizeConfigKey)
+var configFields = map[string]configField{
+	"logfilepath":            {"LogFilePath", stringField(func(l *LogRotateX) *string { return &l.LogFilePath })},
+	"async":                  {"Async", boolField(func(l *LogRotateX) *bool { return &l.Async })},
+	"maxsize":                {"MaxSize", intField(func(l *LogRotateX) *int { return &l.MaxSize })},
+	"maxbytes":               {"MaxBytes", byteSizeField(func(l *LogRotateX) *ByteSize { return &l.MaxBytes })},
+	"maxtotalsize":           {"MaxTotalSize", byteSizeField(func(l *LogRotateX) *ByteSize { return &l.MaxTotalSize })},
+	"minfreespace":           {"MinFreeSpace", byteSizeField(func(l *LogRotateX) *ByteSize { return &l.MinFreeSpace })},
+	"minfreepercent":         {"MinFreePercent", floatField(func(l *LogRotateX) *float64 { return &l.MinFreePercent })},
+	"freespacecheckinterval": {"FreeSpaceCheckInterval", durationField(func(l *LogRotateX) *time.Duration { return &l.FreeSpaceCheckInterval })},
+	"maxage":                 {"MaxAge", daysField(func(l *LogRotateX) *int { return &l.MaxAge })},
+	"maxfiles":               {"MaxFiles", intField(func(l *LogRotateX) *int { return &l.MaxFiles })},
+	"localtime":              {"LocalTime", boolField(func(l *LogRotateX) *bool { return &l.LocalTime })},
+	"compress":               {"Compress", boolField(func(l *LogRotateX) *bool { return &l.Compress })},
+	"datedirlayout":          {"DateDirLayout", boolField(func(l *LogRotateX) *bool { return &l.DateDirLayout })},
+	"rotatebyday":            {"RotateByDay", boolField(func(l *LogRotateX) *bool { return &l.RotateByDay })},
+	"numberedbackups":        {"NumberedBackups", boolField(func(l *LogRotateX) *bool { return &l.NumberedBackups })},
+	"linkname":               {"LinkName", stringField(func(l *LogRotateX) *string { return &l.LinkName })},
+	"backuplinkname":         {"BackupLinkName", stringField(func(l *LogRotateX) *string { return &l.BackupLinkName })},
+	"rotateschedule":         {"RotateSchedule", stringField(func(l *LogRotateX) *string { return &l.RotateSchedule })},
+	"backgroundrotate":       {"BackgroundRotate", boolField(func(l *LogRotateX) *bool { return &l.BackgroundRotate })},
+	"cleanupinterval":        {"CleanupInterval", durationField(func(l *LogRotateX) *time.Duration { return &l.CleanupInterval })},
+	"compresstype": {"CompressType", func(l *LogRotateX, _ *BufCfg, v string) error {
+		t, err := ParseCompressType(v)
+		if err == nil {
+			l.CompressType = t
+		}
+		return err
+	}},
+	"multiprocess":  {"MultiProcess", boolField(func(l *LogRotateX) *bool { return &l.MultiProcess })},
+	"watchfile":     {"WatchFile", boolField(func(l *LogRotateX) *bool { return &l.WatchFile })},
+	"watchinterval": {"WatchInterval", durationField(func(l *LogRotateX) *time.Duration { return &l.WatchInterval })},
+	"rotatemode": {"RotateMode", func(l *LogRotateX, _ *BufCfg, v string) error {
+		l.RotateMode = RotateMode(strings.ToLower(v))
+		return nil
+	}},
+	"diskfullpolicy": {"DiskFullPolicy", func(l *LogRotateX, _ *BufCfg, v string) error {
+		l.DiskFullPolicy = DiskFullPolicy(strings.ToLower(v))
+		return nil
+	}},
+}
+
+// bufConfigFields 是 BufCfg 的可配置字段 (buffer 小节), 键为规范化的配置项名称
+var bufConfigFields = map[string]configField{
+	"maxbuffersize": {"BufCfg.MaxBufferSize", func(_ *LogRotateX, b *BufCfg, v string) error {
+		n, err := ParseByteSize(v)
+		if err == nil {
+			b.MaxBufferSize = int(n)
+		}
+		return err
+	}},
+	"flushinterval": {"BufCfg.FlushInterval", func(_ *LogRotateX, b *BufCfg, v string) error {
+		d, err := parseConfigDuration(v)
+		if err == nil {
+			b.FlushInterval = d
+		}
+		return err
+	}},
+}
+
+// stringField 返回设置字符串字段的函数
+func stringField(field func(*LogRotateX) *string) func(*LogRotateX, *BufCfg, string) error {
+	return func(l *LogRotateX, _ *BufCfg, v string) error {
+		*field(l) = v
+		return nil
+	}
+}
+
+// boolField 返回设置布尔字段的函数
+func boolField(field func(*LogRotateX) *bool) func(*LogRotateX, *BufCfg, string) error {
+	return func(l *LogRotateX, _ *BufCfg, v string) error {
+		b, err := strconv.ParseBool(v)
+		if err != nil {
+			return errors.New("invalid boolean")
+		}
+		*field(l) = b
+		return nil
+	}
+}
+
+// intField 返回设置整数字段的函数
+func intField(field func(*LogRotateX) *int) func(*LogRotateX, *BufCfg, string) error {
+	return func(l *LogRotateX, _ *BufCfg, v string) error {
+		n, err := strconv.Atoi(v)
+		if err != nil {
+			return errors.New("invalid integer")
+		}
+		*field(l) = n
+		return nil
+	}
+}
+
+// floatField 返回设置浮点数字段的函数
+func floatField(field func(*LogRotateX) *float64) func(*LogRotateX, *BufCfg, string) error {
+	return func(l *LogRotateX, _ *BufCfg, v string) error {
+		f, err := strconv.ParseFloat(v, 64)
+		if err != nil {
+			return errors.New("invalid number")
+		}
+		*field(l) = f
+		return nil
+	}
+}
+
+// byteSizeField 返回设置字节大小字段的函数
+func byteSizeField(field func(*LogRotateX) *ByteSize) func(*LogRotateX, *BufCfg, string) error {
+	return func(l *LogRotateX, _ *BufCfg, v string) error {
+		n, err := ParseByteSize(v)
+		if err != nil {
+			return err
+		}
+		*field(l) = n
+		return nil
+	}
+}
+
+// durationField 返回设置时长字段的函数
+func durationField(field func(*LogRotateX) *time.Duration) func(*LogRotateX, *BufCfg, string) error {
+	return func(l *LogRotateX, _ *BufCfg, v string) error {
+		d, err := parseConfigDuration(v)
+		if err != nil {
+			return err
+		}
+		*field(l) = d
+		return nil
+	}
+}
+
+// daysField 返回设置天数字段的函数
+func daysField(field func(*LogRotateX) *int) func(*LogRotateX, *BufCfg, string) error {
+	return func(l *LogRotateX, _ *BufCfg, v string) error {
+		n, err := parseDays(v)
+		if err != nil {
+			return err
+		}
+		*field(l) = n
+		return nil
+	}
+}
+
+// applyConfigValue 将一个配置项设置到对应的字段
+//
+// 返回值:
+//   - *FieldError: 配置项未知或值无法解析时返回错误
+func applyConfigValue(l *LogRotateX, buf *BufCfg, v configValue) *FieldError {
+	fields := configFields
+	name := v.key
+	switch v.section {
+	case "":
+	case bufferSection:
+		fields = bufConfigFields
+		name = bufferSection + "." + v.key
+	default:
+		return &FieldError{Field: v.section + "." + v.key, Value: v.value, Err: errUnknownField}
+	}
+
+	f, ok := fields[normalizeConfigKey(v.key)]
+	if !ok {
+		return &FieldError{Field: name, Value: v.value, Err: errUnknownField}
+	}
+	if err := f.set(l, buf, strings.TrimSpace(v.value)); err != nil {
+		return &FieldError{Field: f.name, Value: v.value, Err: err}
+	}
+	return nil
+}
+
+// normalizeConfigKey 规范化配置项名称: 转为小写并去掉下划线和连字符, 使 compress_type、compress-type、CompressType 等价
+func normalizeConfigKey(key string) string {
+	key = strings.ToLower(strings.TrimSpace(key))
+	return strings.NewReplacer("_", "", "-", "").Replace(key)
+}
+
+// supportedCompressTypes 是 comprx 支持的压缩类型
+var supportedCompressTypes = []comprx.CompressType{
+	comprx.CompressTypeZip,
+	comprx.CompressTypeTar,
+	comprx.CompressTypeTgz,
+	comprx.CompressTypeTarGz,
+	comprx.CompressTypeGz,
+	comprx.CompressTypeBz2,
+	comprx.CompressTypeBzip2,
+	comprx.CompressTypeZlib,
+}
+
+// ParseCompressType 解析压缩类型, 不区分大小写, 可以省略开头的点号 (如 "gz"、".tar.gz")。
+//
+// 参数:
+//   - s: 压缩类型
+//
+// 返回值:
+//   - comprx.CompressType: 对应的压缩类型
+//   - error: 不是 comprx 支持的类型时返回错误
+func ParseCompressType(s string) (comprx.CompressType, error) {
+	s = strings.ToLower(strings.TrimSpace(s))
+	if s != "" && !strings.HasPrefix(s, ".") {
+		s = "." + s
+	}
+	for _, t := range supportedCompressTypes {
+		if t.String() == s {
+			return t, nil
+		}
+	}
+
+	names := make([]string, len(supportedCompressTypes))
+	for i, t := range supportedCompressTypes {
+		names[i] = strings.TrimPrefix(t.String(), ".")
+	}
+	return "", fmt.Errorf("unsupported compress type, supported: %s", strings.Join(names, ", "))
+}
+
+// parseDays 解析天数: 整数表示天数, 也可以是 "7d"、"2w" 或整天的时长 (如 "168h")
+func parseDays(s string) (int, error) {
+	if n, err := strconv.Atoi(s); err == nil {
+		return n, nil
+	}
+	d, err := parseConfigDuration(s)
+	if err != nil {
+		return 0, err
+	}
+	if d%(24*time.Hour) != 0 {
+		return 0, errors.New("must be a whole number of days")
+	}
+	return int(d / (24 * time.Hour)), nil
+}
+
+// parseConfigDuration 解析时长, 在 time.ParseDuration 的基础上支持天 ("7d") 和周 ("2w")
+func parseConfigDuration(s string) (time.Duration, error) {
+	if len(s) > 1 {
+		unit := time.Duration(0)
+		switch s[len(s)-1] {
+		case 'd', 'D':
+			unit = 24 * time.Hour
+		case 'w', 'W':
+			unit = 7 * 24 * time.Hour
+		}
+		if unit > 0 {
+			n, err := strconv.Atoi(s[:len(s)-1])
+			if err != nil {
+				return 0, errors.New("invalid duration")
+			}
+			return time.Duration(n) * unit, nil
+		}
+	}
+
+	d, err := time.ParseDuration(s)
+	if err != nil {
+		return 0, errors.New("invalid duration")
+	}
+	return d, nil
+}
+
+// parseJSONConfig 解析 JSON 配置, 顶层必须是对象, buffer 的值可以是对象
+func parseJSONConfig(data []byte) ([]configValue, error) {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	var root map[string]any
+	if err := dec.Decode(&root); err != nil {
+		return nil, fmt.Errorf("invalid json config: %w", err)
+	}
+	return flattenConfig("json", root)
+}
+
+// parseYAMLConfig 使用 yaml.v3 解析 YAML 配置, 顶层必须是映射, buffer 的值可以是映射
+func parseYAMLConfig(data []byte) ([]configValue, error) {
+	var root map[string]any
+	if err := yaml.Unmarshal(data, &root); err != nil {
+		return nil, fmt.Errorf("invalid yaml config: %w", err)
+	}
+	return flattenConfig("yaml", root)
+}
+
+// parseTOMLConfig 使用 BurntSushi/toml 解析 TOML 配置, buffer 的配置放在 [buffer] 表中
+func parseTOMLConfig(data []byte) ([]configValue, error) {
+	var root map[string]any
+	if err := toml.Unmarshal(data, &root); err != nil {
+		return nil, fmt.Errorf("invalid toml config: %w", err)
+	}
+	return flattenConfig("toml", root)
+}
+
+// flattenConfig 将解码后的配置展开为配置项, 只允许一层嵌套的小节, 值为空 (null) 的配置项使用默认值。
+//
+// 参数:
+//   - format: 配置格式的名称, 用于错误信息
+//   - root: 解码后的顶层对象
+//
+// 返回值:
+//   - []configValue: 顶层配置项在前, 各部分内按名称排序
+//   - error: 包含列表、多层嵌套或其他不支持的值时返回错误
+func flattenConfig(format string, root map[string]any) ([]configValue, error) {
+	var values []configValue
+	var walk func(section string, obj map[string]any) error
+	walk = func(section string, obj map[string]any) error {
+		// 按名称排序, 保证错误的顺序稳定
+		keys := make([]string, 0, len(obj))
+		for k := range obj {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+
+		var sections []string
+		for _, k := range keys {
+			var value string
+			switch v := obj[k].(type) {
+			case nil:
+				// null 表示使用默认值
+				continue
+			case string:
+				value = v
+			case json.Number:
+				value = v.String()
+			case int:
+				value = strconv.Itoa(v)
+			case int64:
+				value = strconv.FormatInt(v, 10)
+			case uint64:
+				value = strconv.FormatUint(v, 10)
+			case float64:
+				value = strconv.FormatFloat(v, 'f', -1, 64)
+			case bool:
+				value = strconv.FormatBool(v)
+			case map[string]any:
+				if section != "" {
+					return fmt.Errorf("invalid %s config: nested object %q in section %q", format, k, section)
+				}
+				// 小节在顶层配置项之后展开
+				sections = append(sections, k)
+				continue
+			default:
+				return fmt.Errorf("invalid %s config: unsupported value for %q", format, k)
+			}
+			values = append(values, configValue{section: section, key: k, value: value})
+		}
+		for _, k := range sections {
+			if err := walk(normalizeConfigKey(k), obj[k].(map[string]any)); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	if err := walk("", root); err != nil {
+		return nil, err
+	}
+	return values, nil
+}
@@ -0,0 +1,226 @@
+// config_test.go 包含了配置文件加载和校验的测试用例。
+// 该文件验证 JSON、YAML、TOML 三种格式得到相同的配置，天数和字节大小等值的解析，
+// 以及无效配置返回合并的 ConfigErrors。
+
+package logrotatex
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"gitee.com/MM-Q/comprx"
+)
+
+// 三种格式的相同配置
+const (
+	testJSONConfig = `{
+	"logfilepath": "logs/app.log",
+	"maxsize": 100,
+	"maxage": "7d",
+	"maxfiles": 5,
+	"compress": true,
+	"compress_type": "gz",
+	"maxtotalsize": "1GiB",
+	"rotatemode": "copytruncate",
+	"rotatebyday": false,
+	"watchinterval": "2s",
+	"buffer": {"maxbuffersize": "64KB", "flushinterval": "3s"}
+}`
+
+	testYAMLConfig = `# 日志配置
+logfilepath: "logs/app.log"
+maxsize: 100
+maxage: 7d   # 保留一周
+maxfiles: 5
+compress: true
+compress_type: gz
+maxtotalsize: 1GiB
+rotatemode: copytruncate
+rotatebyday: false
+watchinterval: 2s
+buffer:
+  maxbuffersize: 64KB
+  flushinterval: '3s'
+`
+
+	testTOMLConfig = `# 日志配置
+logfilepath = "logs/app.log"
+maxsize = 100
+maxage = "7d" # 保留一周
+maxfiles = 5
+compress = true
+compress-type = "gz"
+maxtotalsize = "1GiB"
+rotatemode = "copytruncate"
+rotatebyday = false
+watchinterval = "2s"
+
+[buffer]
+maxbuffersize = "64KB"
+flushinterval = "3s"
+`
+)
+
+// TestFromConfig 测试三种格式解析出相同的配置, 未配置的字段与 NewLogRotateX 一致
+func TestFromConfig(t *testing.T) {
+	want := NewLogRotateX("logs/app.log")
+	want.MaxSize = 100
+	want.MaxAge = 7
+	want.MaxFiles = 5
+	want.Compress = true
+	want.CompressType = comprx.CompressTypeGz
+	want.MaxTotalSize = ByteSize(1 << 30)
+	want.RotateMode = RotateModeCopyTruncate
+	want.RotateByDay = false
+	want.WatchInterval = 2 * time.Second
+
+	for format, data := range map[ConfigFormat]string{
+		ConfigJSON: testJSONConfig,
+		ConfigYAML: testYAMLConfig,
+		ConfigTOML: testTOMLConfig,
+	} {
+		t.Run(string(format), func(t *testing.T) {
+			l, buf, err := FromConfig(strings.NewReader(data), format)
+			isNil(err, t)
+			equals(want, l, t)
+			equals(&BufCfg{MaxBufferSize: 64 * 1024, FlushInterval: 3 * time.Second}, buf, t)
+		})
+	}
+}
+
+// TestFromConfig_Syntax 测试 YAML 和 TOML 的完整语法, 如流式映射、多行字符串、点分键和内联表
+func TestFromConfig_Syntax(t *testing.T) {
+	for format, data := range map[ConfigFormat]string{
+		ConfigYAML: "linkname: >-\n  logs/current.log\nmaxage: \"7d\" # 保留一周\nbuffer: {flushinterval: 3s}\n",
+		ConfigTOML: "linkname = \"\"\"\nlogs/current.log\"\"\"\nmaxage = '7d' # 保留一周\nbuffer.flushinterval = \"3s\"\n",
+	} {
+		t.Run(string(format), func(t *testing.T) {
+			l, buf, err := FromConfig(strings.NewReader(data), format)
+			isNil(err, t)
+			equals("logs/current.log", l.LinkName, t)
+			equals(7, l.MaxAge, t)
+			equals(3*time.Second, buf.FlushInterval, t)
+		})
+	}
+
+	// 内联表与 [buffer] 表等价
+	_, buf, err := FromConfig(strings.NewReader("buffer = {maxbuffersize = \"64KB\"}\n"), ConfigTOML)
+	isNil(err, t)
+	equals(64*1024, buf.MaxBufferSize, t)
+
+	// 列表和多层嵌套不是有效的配置项
+	for format, data := range map[ConfigFormat]string{
+		ConfigYAML: "buffer:\n  inner:\n    maxbuffersize: 1KB\n",
+		ConfigTOML: "[buffer.inner]\nmaxbuffersize = \"1KB\"\n",
+	} {
+		if _, _, err := FromConfig(strings.NewReader(data), format); err == nil {
+			t.Fatalf("期望 %s 多层嵌套返回错误", format)
+		}
+	}
+}
+
+// TestLoadConfig 测试按扩展名选择格式, 没有 buffer 小节时 BufCfg 为 nil
+func TestLoadConfig(t *testing.T) {
+	dir := makeTempDir("TestLoadConfig", t)
+	defer func() { _ = os.RemoveAll(dir) }()
+
+	path := filepath.Join(dir, "log.yml")
+	isNil(os.WriteFile(path, []byte("logfilepath: logs/app.log\nmaxage: 168h\n"), 0644), t)
+	l, buf, err := LoadConfig(path)
+	isNil(err, t)
+	equals(7, l.MaxAge, t)
+	equals((*BufCfg)(nil), buf, t)
+
+	if _, _, err = LoadConfig(filepath.Join(dir, "log.ini")); err == nil {
+		t.Fatal("期望不支持的扩展名返回错误")
+	}
+}
+
+// TestFromConfig_Errors 测试所有配置项错误合并返回
+func TestFromConfig_Errors(t *testing.T) {
+	data := `
+maxsize = "big"
+maxage = "36h"
+compress_type = "rar"
+unknown = 1
+
+[buffer]
+flushinterval = "soon"
+`
+	_, _, err := FromConfig(strings.NewReader(data), ConfigTOML)
+	var errs ConfigErrors
+	if !errors.As(err, &errs) {
+		t.Fatalf("期望错误类型为 ConfigErrors, 实际: %v", err)
+	}
+	var fields []string
+	for _, e := range errs {
+		fields = append(fields, e.Field)
+	}
+	equals([]string{"CompressType", "MaxAge", "MaxSize", "unknown", "BufCfg.FlushInterval"}, fields, t)
+	if !errors.Is(err, errUnknownField) {
+		t.Fatal("期望 errors.Is 能判断未知配置项")
+	}
+
+	// 语法错误直接返回
+	for format, data := range map[ConfigFormat]string{
+		ConfigJSON: `{"maxsize": [10]}`,
+		ConfigYAML: "maxsize 10\n",
+		ConfigTOML: "files = [1, 2]\n",
+	} {
+		if _, _, err := FromConfig(strings.NewReader(data), format); err == nil {
+			t.Fatalf("期望 %s 语法错误", format)
+		}
+	}
+}
+
+// TestValidate 测试字段取值和组合的校验
+func TestValidate(t *testing.T) {
+	isNil(NewLogRotateX("logs/app.log").Validate(), t)
+	isNil(DefBufCfg().Validate(), t)
+
+	l := &LogRotateX{
+		LogFilePath:      "logs/%Y/app.log",
+		MaxAge:           -1,
+		MaxBytes:         10 * MegaByte,
+		MaxTotalSize:     MegaByte,
+		MinFreePercent:   100,
+		CompressType:     "gz",
+		RotateMode:       "move",
+		DiskFullPolicy:   "retry",
+		RotateSchedule:   "0 25 * * *",
+		BackgroundRotate: true,
+	}
+	var errs ConfigErrors
+	if !errors.As(l.Validate(), &errs) {
+		t.Fatal("期望返回 ConfigErrors")
+	}
+	var fields []string
+	for _, e := range errs {
+		fields = append(fields, e.Field)
+	}
+	equals([]string{"MaxAge", "MinFreePercent", "LogFilePath", "RotateSchedule", "CompressType",
+		"RotateMode", "DiskFullPolicy", "MaxTotalSize"}, fields, t)
+
+	err := (&BufCfg{FlushInterval: time.Millisecond}).Validate()
+	if !errors.As(err, &errs) || errs[0].Field != "BufCfg.FlushInterval" {
+		t.Fatalf("期望刷新间隔错误, 实际: %v", err)
+	}
+}
+
+// TestParseDays 测试天数的各种写法
+func TestParseDays(t *testing.T) {
+	for s, want := range map[string]int{"7": 7, "7d": 7, "2w": 14, "48h": 2, "0": 0} {
+		n, err := parseDays(s)
+		isNil(err, t)
+		equals(want, n, t)
+	}
+	for _, s := range []string{"1.5d", "25h", "week"} {
+		if _, err := parseDays(s); err == nil {
+			t.Fatalf("期望 %q 解析失败", s)
+		}
+	}
+}
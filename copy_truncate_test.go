@@ -0,0 +1,125 @@
+// copy_truncate_test.go 包含了 copytruncate 轮转方式的测试用例。
+// 该文件验证复制后截断能够保持当前文件的 inode 不变，
+// 使其他持有文件句柄的程序在轮转后继续写入当前文件。
+
+package logrotatex
+
+import (
+	"os"
+	"testing"
+)
+
+// TestCopyTruncate_SecondHandle 测试轮转后另一个句柄继续写入当前文件而不是备份文件
+func TestCopyTruncate_SecondHandle(t *testing.T) {
+	originalCurrentTime := currentTime
+	defer func() { currentTime = originalCurrentTime }()
+	currentTime = fakeTime
+
+	dir := makeTempDir("TestCopyTruncate_SecondHandle", t)
+	defer func() { _ = os.RemoveAll(dir) }()
+
+	l := &LogRotateX{LogFilePath: logFile(dir), RotateMode: RotateModeCopyTruncate}
+	defer func() { _ = l.Close() }()
+
+	_, err := l.Write([]byte("main1\n"))
+	isNil(err, t)
+
+	// 模拟持有同一文件句柄的旁路程序
+	sidecar, err := os.OpenFile(logFile(dir), os.O_WRONLY|os.O_APPEND, 0600)
+	isNil(err, t)
+	defer func() { _ = sidecar.Close() }()
+
+	_, err = sidecar.Write([]byte("sidecar1\n"))
+	isNil(err, t)
+
+	before, err := os.Stat(logFile(dir))
+	isNil(err, t)
+
+	isNil(l.Rotate(), t)
+
+	// 备份文件包含轮转前两个句柄写入的全部数据, 当前文件被截断
+	existsWithContent(backupFile(dir), []byte("main1\nsidecar1\n"), t)
+	existsWithContent(logFile(dir), []byte{}, t)
+
+	// 当前文件仍是同一个 inode
+	after, err := os.Stat(logFile(dir))
+	isNil(err, t)
+	equals(true, os.SameFile(before, after), t)
+
+	// 轮转后两个句柄都写入当前文件
+	_, err = sidecar.Write([]byte("sidecar2\n"))
+	isNil(err, t)
+	_, err = l.Write([]byte("main2\n"))
+	isNil(err, t)
+	existsWithContent(logFile(dir), []byte("sidecar2\nmain2\n"), t)
+	existsWithContent(backupFile(dir), []byte("main1\nsidecar1\n"), t)
+}
+
+// TestCopyTruncate_SizeReset 测试按大小轮转后当前文件大小被重置
+func TestCopyTruncate_SizeReset(t *testing.T) {
+	originalCurrentTime := currentTime
+	defer func() { currentTime = originalCurrentTime }()
+	currentTime = fakeTime
+
+	dir := makeTempDir("TestCopyTruncate_SizeReset", t)
+	defer func() { _ = os.RemoveAll(dir) }()
+
+	l := &LogRotateX{LogFilePath: logFile(dir), MaxSize: 1, RotateMode: RotateModeCopyTruncate}
+	defer func() { _ = l.Close() }()
+
+	first := make([]byte, megabyte-10)
+	_, err := l.Write(first)
+	isNil(err, t)
+
+	// 超出限制触发 copytruncate 轮转
+	second := []byte("overflow data\n")
+	_, err = l.Write(second)
+	isNil(err, t)
+
+	existsWithContent(backupFile(dir), first, t)
+	existsWithContent(logFile(dir), second, t)
+	equals(int64(len(second)), l.size, t)
+}
+
+// TestCopyTruncate_KeepAppended 测试截断之后、重新打开之前其他程序追加的内容不会被清空
+func TestCopyTruncate_KeepAppended(t *testing.T) {
+	originalCurrentTime := currentTime
+	originalCopyTruncate := copyTruncateFile
+	defer func() {
+		currentTime = originalCurrentTime
+		copyTruncateFile = originalCopyTruncate
+	}()
+	currentTime = fakeTime
+
+	dir := makeTempDir("TestCopyTruncate_KeepAppended", t)
+	defer func() { _ = os.RemoveAll(dir) }()
+
+	// 截断后模拟旁路程序立即追加
+	copyTruncateFile = func(src, dst string, mode os.FileMode) error {
+		if err := copyTruncate(src, dst, mode); err != nil {
+			return err
+		}
+		f, err := os.OpenFile(src, os.O_WRONLY|os.O_APPEND, 0600)
+		if err != nil {
+			return err
+		}
+		defer func() { _ = f.Close() }()
+		_, err = f.Write([]byte("sidecar\n"))
+		return err
+	}
+
+	l := &LogRotateX{LogFilePath: logFile(dir), RotateMode: RotateModeCopyTruncate}
+	defer func() { _ = l.Close() }()
+
+	_, err := l.Write([]byte("main1\n"))
+	isNil(err, t)
+	isNil(l.Rotate(), t)
+
+	existsWithContent(backupFile(dir), []byte("main1\n"), t)
+	existsWithContent(logFile(dir), []byte("sidecar\n"), t)
+	equals(int64(len("sidecar\n")), l.size, t)
+
+	_, err = l.Write([]byte("main2\n"))
+	isNil(err, t)
+	existsWithContent(logFile(dir), []byte("sidecar\nmain2\n"), t)
+}
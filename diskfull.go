@@ -0,0 +1,139 @@
+// diskfull.go 实现了logrotatex包的磁盘已满处理策略。
+// 写入日志文件时遇到磁盘已满 (ENOSPC) 或超出磁盘配额 (EDQUOT)，按 DiskFullPolicy
+// 删除最旧的备份文件后重试、改写到备用输出或丢弃并计数，并以限频的警告代替重复的错误。
+
+package logrotatex
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"time"
+)
+
+// DiskFullPolicy 是写入时磁盘已满的处理策略
+type DiskFullPolicy string
+
+const (
+	// DiskFullPolicyFail 将写入错误返回给调用方 (默认)
+	DiskFullPolicyFail DiskFullPolicy = "fail"
+
+	// DiskFullPolicyEvict 删除最旧的备份文件后重试一次, 没有可删除的备份文件或重试仍失败时返回错误
+	DiskFullPolicyEvict DiskFullPolicy = "evict"
+
+	// DiskFullPolicyFallback 将未写入的数据写入 FallbackWriter (默认为 os.Stderr)
+	DiskFullPolicyFallback DiskFullPolicy = "fallback"
+
+	// DiskFullPolicyDrop 丢弃未写入的数据并计数, 不向调用方返回错误
+	DiskFullPolicyDrop DiskFullPolicy = "drop"
+)
+
+// diskFullWarnInterval 是磁盘已满警告的最小间隔, 间隔内的重复失败只计数
+const diskFullWarnInterval = time.Minute
+
+// writeFile 将数据写入日志文件, 测试中替换为返回 syscall.ENOSPC 等错误的模拟实现
+var writeFile = func(f *os.File, p []byte) (int, error) {
+	return f.Write(p)
+}
+
+// DroppedWrites 返回因磁盘已满被丢弃的写入次数 (DiskFullPolicyDrop)
+func (l *LogRotateX) DroppedWrites() int64 {
+	return l.droppedWrites.Load()
+}
+
+// handleDiskFull 按 DiskFullPolicy 处理磁盘已满导致的写入失败, 调用方需持有 l.mu。
+//
+// 参数:
+//   - p: 本次写入的全部数据
+//   - n: 已写入日志文件的字节数
+//   - err: 写入日志文件返回的错误
+//
+// 返回值:
+//   - int: 返回给调用方的写入字节数, 数据被妥善处理时为 len(p)
+//   - error: 数据未能处理时返回错误
+func (l *LogRotateX) handleDiskFull(p []byte, n int, err error) (int, error) {
+	l.warnDiskFull(err)
+	rest := p[n:]
+
+	switch l.DiskFullPolicy {
+	case DiskFullPolicyEvict:
+		if l.evictForWrite(int64(len(rest))) > 0 {
+			m, retryErr := writeFile(l.file, rest)
+			l.size += int64(m)
+			if retryErr == nil {
+				return len(p), nil
+			}
+			n, err = n+m, retryErr
+		}
+
+	case DiskFullPolicyFallback:
+		var w io.Writer = os.Stderr
+		if l.FallbackWriter != nil {
+			w = l.FallbackWriter
+		}
+		if _, fallbackErr := w.Write(rest); fallbackErr != nil {
+			return n, fmt.Errorf("failed to write to fallback writer: %w", fallbackErr)
+		}
+		return len(p), nil
+
+	case DiskFullPolicyDrop:
+		l.droppedWrites.Add(1)
+		return len(p), nil
+	}
+
+	return n, fmt.Errorf("failed to write to file: %w", err)
+}
+
+// evictForWrite 从最旧的备份文件开始删除, 直到删除的文件总大小不小于 need (至少删除一个)。
+//
+// 参数:
+//   - need: 需要释放的字节数
+//
+// 返回值:
+//   - int: 删除的文件数量
+func (l *LogRotateX) evictForWrite(need int64) int {
+	unlock, err := l.lockCleanup()
+	if err != nil {
+		return 0
+	}
+	defer unlock()
+
+	files, err := l.oldLogFiles()
+	if err != nil || len(files) == 0 {
+		return 0
+	}
+
+	// 从最旧的文件开始 (files 按时间从新到旧排列)
+	var remove []logInfo
+	var freed int64
+	for i := len(files) - 1; i >= 0; i-- {
+		remove = append(remove, files[i])
+		freed += files[i].Size()
+		if freed >= need {
+			break
+		}
+	}
+
+	if err := l.executeCleanup(remove, nil); err != nil {
+		l.reportError(OpDiskFull, l.dir(), fmt.Errorf("failed to remove backups: %w", err))
+	}
+	return len(remove)
+}
+
+// warnDiskFull 报告磁盘已满警告, 每个 diskFullWarnInterval 最多报告一次, 并附带期间被抑制的次数。
+func (l *LogRotateX) warnDiskFull(err error) {
+	now := time.Now()
+	if !l.lastDiskFullWarn.IsZero() && now.Sub(l.lastDiskFullWarn) < diskFullWarnInterval {
+		l.suppressedWarns++
+		return
+	}
+
+	if l.suppressedWarns > 0 {
+		err = fmt.Errorf("disk full (policy %s, %d similar failures suppressed): %w", l.DiskFullPolicy, l.suppressedWarns, err)
+	} else {
+		err = fmt.Errorf("disk full (policy %s): %w", l.DiskFullPolicy, err)
+	}
+	l.reportError(OpDiskFull, l.filename(), err)
+	l.lastDiskFullWarn = now
+	l.suppressedWarns = 0
+}
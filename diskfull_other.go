@@ -0,0 +1,10 @@
+// diskfull_other.go 为无法识别磁盘已满错误的平台提供占位实现。
+// 在这些平台上 DiskFullPolicy 不生效，写入错误直接返回给调用方。
+//go:build !unix && !windows
+
+package logrotatex
+
+// isDiskFull 在当前平台始终返回 false。
+func isDiskFull(_ error) bool {
+	return false
+}
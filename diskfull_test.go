@@ -0,0 +1,116 @@
+// diskfull_test.go 包含了磁盘已满处理策略 (DiskFullPolicy) 的测试用例。
+// 该文件通过注入返回 syscall.ENOSPC 的写入函数验证各策略的行为，
+// 以及重复失败时警告的限频。
+//go:build unix || windows
+
+package logrotatex
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"syscall"
+	"testing"
+)
+
+// fullDisk 替换 writeFile, 前 failures 次写入返回 syscall.ENOSPC, 返回恢复函数
+func fullDisk(failures int) func() {
+	original := writeFile
+	writeFile = func(f *os.File, p []byte) (int, error) {
+		if failures > 0 {
+			failures--
+			return 0, &os.PathError{Op: "write", Path: f.Name(), Err: syscall.ENOSPC}
+		}
+		return f.Write(p)
+	}
+	return func() { writeFile = original }
+}
+
+// TestDiskFullPolicy_Fail 测试默认策略将磁盘已满错误返回给调用方
+func TestDiskFullPolicy_Fail(t *testing.T) {
+	dir := makeTempDir("TestDiskFullPolicy_Fail", t)
+	defer func() { _ = os.RemoveAll(dir) }()
+	defer fullDisk(1)()
+
+	l := &LogRotateX{LogFilePath: logFile(dir)}
+	defer func() { _ = l.Close() }()
+
+	n, err := l.Write([]byte("lost"))
+	equals(0, n, t)
+	if !errors.Is(err, syscall.ENOSPC) {
+		t.Fatalf("期望返回 ENOSPC, 实际: %v", err)
+	}
+	equals(DiskFullPolicyFail, l.DiskFullPolicy, t)
+}
+
+// TestDiskFullPolicy_Evict 测试删除最旧的备份文件后重试写入
+func TestDiskFullPolicy_Evict(t *testing.T) {
+	dir := makeTempDir("TestDiskFullPolicy_Evict", t)
+	defer func() { _ = os.RemoveAll(dir) }()
+
+	oldest := filepath.Join(dir, "foobar_20261014090000.log")
+	newest := filepath.Join(dir, "foobar_20261015090000.log")
+	isNil(os.WriteFile(oldest, make([]byte, 100), 0600), t)
+	isNil(os.WriteFile(newest, make([]byte, 100), 0600), t)
+
+	l := &LogRotateX{LogFilePath: logFile(dir), DiskFullPolicy: DiskFullPolicyEvict}
+	defer func() { _ = l.Close() }()
+
+	defer fullDisk(1)()
+	b := []byte("retried")
+	n, err := l.Write(b)
+	isNil(err, t)
+	equals(len(b), n, t)
+	existsWithContent(logFile(dir), b, t)
+	notExist(oldest, t)
+	exists(newest, t)
+
+	// 没有可删除的备份文件时返回错误
+	isNil(os.Remove(newest), t)
+	defer fullDisk(1)()
+	if _, err := l.Write([]byte("lost")); !errors.Is(err, syscall.ENOSPC) {
+		t.Fatalf("期望返回 ENOSPC, 实际: %v", err)
+	}
+}
+
+// TestDiskFullPolicy_Fallback 测试将未写入的数据写入备用输出
+func TestDiskFullPolicy_Fallback(t *testing.T) {
+	dir := makeTempDir("TestDiskFullPolicy_Fallback", t)
+	defer func() { _ = os.RemoveAll(dir) }()
+	defer fullDisk(1)()
+
+	var fallback bytes.Buffer
+	l := &LogRotateX{LogFilePath: logFile(dir), DiskFullPolicy: DiskFullPolicyFallback, FallbackWriter: &fallback}
+	defer func() { _ = l.Close() }()
+
+	b := []byte("to fallback")
+	n, err := l.Write(b)
+	isNil(err, t)
+	equals(len(b), n, t)
+	equals("to fallback", fallback.String(), t)
+
+	// 磁盘恢复后继续写入日志文件
+	_, err = l.Write([]byte("to file"))
+	isNil(err, t)
+	existsWithContent(logFile(dir), []byte("to file"), t)
+}
+
+// TestDiskFullPolicy_Drop 测试丢弃数据并计数, 重复失败只打印一次警告
+func TestDiskFullPolicy_Drop(t *testing.T) {
+	dir := makeTempDir("TestDiskFullPolicy_Drop", t)
+	defer func() { _ = os.RemoveAll(dir) }()
+	defer fullDisk(3)()
+
+	l := &LogRotateX{LogFilePath: logFile(dir), DiskFullPolicy: DiskFullPolicyDrop}
+	defer func() { _ = l.Close() }()
+
+	for i := 0; i < 3; i++ {
+		n, err := l.Write([]byte("dropped"))
+		isNil(err, t)
+		equals(7, n, t)
+	}
+	equals(int64(3), l.DroppedWrites(), t)
+	equals(2, l.suppressedWarns, t)
+	equals(int64(0), l.size, t)
+}
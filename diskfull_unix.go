@@ -0,0 +1,15 @@
+// diskfull_unix.go 实现了类Unix系统下的磁盘已满错误识别。
+// 该文件将 ENOSPC (磁盘已满) 和 EDQUOT (超出磁盘配额) 识别为磁盘已满错误。
+//go:build unix
+
+package logrotatex
+
+import (
+	"errors"
+	"syscall"
+)
+
+// isDiskFull 检查错误是否由磁盘已满或超出磁盘配额引起。
+func isDiskFull(err error) bool {
+	return errors.Is(err, syscall.ENOSPC) || errors.Is(err, syscall.EDQUOT)
+}
@@ -0,0 +1,23 @@
+// diskfull_windows.go 实现了Windows系统下的磁盘已满错误识别。
+// 该文件将 ERROR_DISK_FULL 和 ERROR_HANDLE_DISK_FULL 识别为磁盘已满错误。
+//go:build windows
+// +build windows
+
+package logrotatex
+
+import (
+	"errors"
+	"syscall"
+)
+
+const (
+	// errorHandleDiskFull 对应 ERROR_HANDLE_DISK_FULL
+	errorHandleDiskFull syscall.Errno = 39
+	// errorDiskFull 对应 ERROR_DISK_FULL
+	errorDiskFull syscall.Errno = 112
+)
+
+// isDiskFull 检查错误是否由磁盘已满引起。
+func isDiskFull(err error) bool {
+	return errors.Is(err, errorDiskFull) || errors.Is(err, errorHandleDiskFull) || errors.Is(err, syscall.ENOSPC)
+}
@@ -0,0 +1,104 @@
+// error_handler.go 实现了logrotatex包的错误报告功能。
+// 后台发生的错误 (轮转后的清理、异步清理、后台调度、符号链接更新、定时刷新等)
+// 无法返回给调用方，统一包装为 *OpError 后交给 ErrorHandler，未设置时写入标准错误输出。
+
+package logrotatex
+
+import (
+	"errors"
+	"fmt"
+	"os"
+)
+
+// 错误报告中的操作名称 (OpError.Op)
+const (
+	OpRotate    = "rotate"    // 轮转日志文件 (包括后台定时轮转)
+	OpCleanup   = "cleanup"   // 按保留规则清理和压缩备份文件
+	OpRemove    = "remove"    // 删除备份文件
+	OpCompress  = "compress"  // 压缩备份文件
+	OpFreeSpace = "freespace" // 剩余空间保护
+	OpDiskFull  = "diskfull"  // 写入时磁盘已满
+	OpClose     = "close"     // 关闭旧的日志文件句柄
+	OpLink      = "link"      // 更新符号链接
+	OpSignal    = "signal"    // 执行信号对应的操作
+	OpFlush     = "flush"     // BufferedWriter 定时刷新
+	OpConfig    = "config"    // 重新加载配置文件
+)
+
+// OpError 是后台操作失败时报告的错误, 记录失败的操作、相关的文件路径和原始错误。
+// 可以通过 errors.As 获取, 并通过 errors.Is 判断原始错误 (如 syscall.ENOSPC)。
+type OpError struct {
+	Op   string // Op 是失败的操作, 如 OpRotate、OpCleanup
+	Path string // Path 是相关的文件路径, 可能为空
+	Err  error  // Err 是原始错误
+}
+
+// Error 实现 error 接口
+func (e *OpError) Error() string {
+	if e.Path == "" {
+		return e.Op + ": " + e.Err.Error()
+	}
+	return e.Op + " " + e.Path + ": " + e.Err.Error()
+}
+
+// Unwrap 返回原始错误
+func (e *OpError) Unwrap() error {
+	return e.Err
+}
+
+// newOpError 将错误包装为 *OpError, 已经是 *OpError 的错误 (包括合并的多个错误) 保持不变
+func newOpError(op, path string, err error) error {
+	var opErr *OpError
+	if errors.As(err, &opErr) {
+		return err
+	}
+	return &OpError{Op: op, Path: path, Err: err}
+}
+
+// reportError 报告后台操作的错误: 设置了 ErrorHandler 时按顺序在回调协程中调用 (不持有内部锁),
+// 否则写入标准错误输出。
+//
+// 参数:
+//   - op: 失败的操作
+//   - path: 相关的文件路径
+//   - err: 原始错误, 为 nil 时不报告
+func (l *LogRotateX) reportError(op, path string, err error) {
+	if err == nil {
+		return
+	}
+	err = newOpError(op, path, err)
+	l.sendEvent(Event{Type: EventError, Path: path, Err: err})
+
+	if l.ErrorHandler == nil {
+		fmt.Fprintf(os.Stderr, "logrotatex: %v\n", err)
+		return
+	}
+	handler := l.ErrorHandler
+	l.enqueueHook(func() { handler(err) })
+}
+
+// reportError 报告定时刷新等后台操作的错误: 设置了 ErrorHandler 时直接调用, 否则写入标准错误输出。
+func (bw *BufferedWriter) reportError(op string, err error) {
+	if err == nil {
+		return
+	}
+	err = newOpError(op, "", err)
+
+	if bw.errorHandler == nil {
+		fmt.Fprintf(os.Stderr, "logrotatex: %v\n", err)
+		return
+	}
+	bw.errorHandler(err)
+}
+
+// reportTargetError 通过目标写入器的错误报告方式报告错误, 目标不支持时写入标准错误输出
+func reportTargetError(target any, op string, err error) {
+	switch t := target.(type) {
+	case *LogRotateX:
+		t.reportError(op, t.LogFilePath, err)
+	case *BufferedWriter:
+		t.reportError(op, err)
+	default:
+		fmt.Fprintf(os.Stderr, "logrotatex: %v\n", newOpError(op, "", err))
+	}
+}
@@ -0,0 +1,106 @@
+// error_handler_test.go 包含了错误报告 (ErrorHandler/OpError) 的测试用例。
+// 该文件验证后台错误被包装为 *OpError 并交给 LogRotateX 和 BufferedWriter 的 ErrorHandler，
+// 以及合并的清理错误可以逐个获取。
+
+package logrotatex
+
+import (
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sync"
+	"syscall"
+	"testing"
+	"time"
+)
+
+// TestOpError 测试错误信息格式和错误链
+func TestOpError(t *testing.T) {
+	err := &OpError{Op: OpRemove, Path: "/var/log/app_1.log", Err: syscall.ENOENT}
+	equals("remove /var/log/app_1.log: "+syscall.ENOENT.Error(), err.Error(), t)
+	if !errors.Is(err, syscall.ENOENT) {
+		t.Fatal("期望 errors.Is 能判断原始错误")
+	}
+
+	// 已经是 *OpError 的错误保持不变
+	equals(error(err), newOpError(OpCleanup, "", err), t)
+
+	// 合并的清理错误可以通过 errors.As 获取
+	joined := cleanupErrors{&OpError{Op: OpCompress, Path: "a", Err: syscall.EIO}, err}
+	var opErr *OpError
+	if !errors.As(joined, &opErr) || opErr.Op != OpCompress {
+		t.Fatalf("期望获取到压缩错误, 实际: %v", opErr)
+	}
+	if !errors.Is(joined, syscall.ENOENT) {
+		t.Fatal("期望 errors.Is 能判断合并错误中的原始错误")
+	}
+	equals(error(joined), newOpError(OpCleanup, "", joined), t)
+}
+
+// TestLogRotateX_ErrorHandler 测试后台错误以 *OpError 交给 ErrorHandler
+func TestLogRotateX_ErrorHandler(t *testing.T) {
+	dir := makeTempDir("TestLogRotateX_ErrorHandler", t)
+	defer func() { _ = os.RemoveAll(dir) }()
+
+	var mu sync.Mutex
+	var reported []error
+	link := filepath.Join(dir, "missing", "current.log")
+	l := &LogRotateX{
+		LogFilePath: logFile(dir),
+		LinkName:    link,
+		ErrorHandler: func(err error) {
+			mu.Lock()
+			defer mu.Unlock()
+			reported = append(reported, err)
+		},
+	}
+
+	// 链接所在目录不存在, 写入成功但更新链接失败
+	_, err := l.Write([]byte("data"))
+	isNil(err, t)
+	isNil(l.Close(), t) // Close 等待错误报告完成
+
+	equals(1, len(reported), t)
+	var opErr *OpError
+	if !errors.As(reported[0], &opErr) {
+		t.Fatalf("期望错误类型为 *OpError, 实际: %T", reported[0])
+	}
+	equals(OpLink, opErr.Op, t)
+	equals(link, opErr.Path, t)
+	if !errors.Is(opErr, fs.ErrNotExist) {
+		t.Fatalf("期望原始错误为 ErrNotExist, 实际: %v", opErr.Err)
+	}
+}
+
+// failingWriteCloser 写入总是失败的 WriteCloser
+type failingWriteCloser struct{}
+
+func (failingWriteCloser) Write([]byte) (int, error) { return 0, syscall.EIO }
+func (failingWriteCloser) Close() error              { return nil }
+
+// TestBufferedWriter_ErrorHandler 测试定时刷新失败时调用 BufferedWriter 的 ErrorHandler
+func TestBufferedWriter_ErrorHandler(t *testing.T) {
+	reported := make(chan error, 10)
+	bw := NewBufferedWriter(failingWriteCloser{}, &BufCfg{
+		FlushInterval: MinFlushInterval,
+		ErrorHandler:  func(err error) { reported <- err },
+	})
+	defer func() { _ = bw.Close() }()
+
+	_, err := bw.Write([]byte("buffered"))
+	isNil(err, t)
+
+	select {
+	case err := <-reported:
+		var opErr *OpError
+		if !errors.As(err, &opErr) || opErr.Op != OpFlush {
+			t.Fatalf("期望 flush 操作的 *OpError, 实际: %v", err)
+		}
+		if !errors.Is(err, syscall.EIO) {
+			t.Fatalf("期望原始错误为 EIO, 实际: %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("定时刷新失败后未调用 ErrorHandler")
+	}
+}
@@ -0,0 +1,110 @@
+// events.go 实现了logrotatex包的生命周期事件通道。
+// Events 返回一个带缓冲的只读通道，打开、轮转、压缩、删除、清理续跑、错误和关闭都会发送一个 Event。
+// 发送不会阻塞写入路径: 缓冲区已满时丢弃事件并计数 (DroppedEvents)，通道在 Close 等待后台协程退出后关闭。
+
+package logrotatex
+
+import "time"
+
+// eventBufferSize 是事件通道的缓冲区大小
+const eventBufferSize = 256
+
+// EventType 是生命周期事件的类型
+type EventType string
+
+const (
+	// EventOpened 打开日志文件 (新建或追加), Path 为日志文件
+	EventOpened EventType = "opened"
+
+	// EventRotated 完成一次轮转, Path 为日志文件, BackupPath 为生成的备份文件, Reason 为轮转原因。
+	// 轮转时先打开新的日志文件, 因此在新文件的 EventOpened 之后发送;
+	// 文件名模式切换时 BackupPath 为空, 新文件在下次写入时打开
+	EventRotated EventType = "rotated"
+
+	// EventCompressStarted 开始压缩备份文件, Path 为备份文件, BackupPath 为压缩文件
+	EventCompressStarted EventType = "compress_started"
+
+	// EventCompressFinished 压缩结束, Path 为备份文件, BackupPath 为压缩文件, 压缩失败时 Err 为失败原因
+	EventCompressFinished EventType = "compress_finished"
+
+	// EventRemoved 清理删除了备份文件, Path 为被删除的文件
+	EventRemoved EventType = "removed"
+
+	// EventCleanupRerun 异步清理期间再次触发, 续跑一轮清理
+	EventCleanupRerun EventType = "cleanup_rerun"
+
+	// EventError 后台操作失败, Path 为相关的文件路径, Err 为 *OpError
+	EventError EventType = "error"
+
+	// EventClosed 实例已关闭, 这是通道关闭前的最后一个事件
+	EventClosed EventType = "closed"
+)
+
+// Event 是生命周期事件
+type Event struct {
+	Type       EventType    // Type 是事件类型
+	Time       time.Time    // Time 是事件发生的时间
+	Path       string       // Path 是事件相关的文件, 含义见各事件类型
+	BackupPath string       // BackupPath 是轮转生成的备份文件或压缩生成的文件
+	Reason     RotateReason // Reason 是轮转原因, 仅 EventRotated 有效
+	Err        error        // Err 是错误, 仅 EventError 和压缩失败的 EventCompressFinished 有效
+}
+
+// Events 返回生命周期事件通道, 第一次调用时创建, 之后返回同一个通道。
+// 通道容量有限, 消费不及时导致缓冲区已满时新事件被丢弃 (见 DroppedEvents), 不会阻塞写入。
+// Close 等待后台协程退出后发送 EventClosed 并关闭通道; 关闭后才第一次调用时返回已关闭的通道。
+//
+// 返回值:
+//   - <-chan Event: 事件通道
+func (l *LogRotateX) Events() <-chan Event {
+	l.eventsMu.Lock()
+	defer l.eventsMu.Unlock()
+
+	if l.events == nil {
+		l.events = make(chan Event, eventBufferSize)
+		if l.closed.Load() {
+			close(l.events)
+			l.eventsClosed = true
+		}
+	}
+	return l.events
+}
+
+// DroppedEvents 返回因事件通道缓冲区已满被丢弃的事件数量
+func (l *LogRotateX) DroppedEvents() int64 {
+	return l.droppedEvents.Load()
+}
+
+// sendEvent 以非阻塞方式发送事件, 未调用过 Events 或通道已关闭时直接返回。
+//
+// 参数:
+//   - event: 事件, Time 为空时使用当前时间
+func (l *LogRotateX) sendEvent(event Event) {
+	l.eventsMu.Lock()
+	defer l.eventsMu.Unlock()
+
+	if l.events == nil || l.eventsClosed {
+		return
+	}
+	if event.Time.IsZero() {
+		event.Time = currentTime()
+	}
+
+	select {
+	case l.events <- event:
+	default:
+		l.droppedEvents.Add(1)
+	}
+}
+
+// closeEvents 发送 EventClosed 并关闭事件通道, 在 Close 等待后台协程退出后调用
+func (l *LogRotateX) closeEvents() {
+	l.sendEvent(Event{Type: EventClosed, Path: l.filename()})
+
+	l.eventsMu.Lock()
+	defer l.eventsMu.Unlock()
+	if l.events != nil && !l.eventsClosed {
+		close(l.events)
+		l.eventsClosed = true
+	}
+}
@@ -0,0 +1,107 @@
+// events_test.go 包含了生命周期事件通道 (Events) 的测试用例。
+// 该文件验证轮转、压缩、删除和关闭按顺序发送事件，通道在 Close 后关闭，
+// 以及缓冲区已满时事件被丢弃并计数。
+
+package logrotatex
+
+import (
+	"os"
+	"strings"
+	"testing"
+	"time"
+)
+
+// TestEvents 测试生命周期事件的类型、顺序和内容
+func TestEvents(t *testing.T) {
+	originalCurrentTime := currentTime
+	originalFake := fakeCurrentTime
+	defer func() {
+		currentTime = originalCurrentTime
+		fakeCurrentTime = originalFake
+	}()
+	currentTime = fakeTime
+	t0 := time.Date(2026, 10, 16, 9, 0, 0, 0, time.UTC)
+	t1, t2 := t0.Add(time.Minute), t0.Add(2*time.Minute)
+	fakeCurrentTime = t0
+
+	dir := makeTempDir("TestEvents", t)
+	defer func() { _ = os.RemoveAll(dir) }()
+
+	l := &LogRotateX{
+		LogFilePath:  logFile(dir),
+		MaxBytes:     10,
+		MaxFiles:     1,
+		Compress:     true,
+		CompressType: ".zip",
+	}
+	events := l.Events()
+	equals(events, l.Events(), t)
+
+	// 按大小轮转, 备份被压缩
+	_, err := l.Write([]byte("012345678"))
+	isNil(err, t)
+	fakeCurrentTime = t1
+	_, err = l.Write([]byte("abc"))
+	isNil(err, t)
+	first := backupFile(dir)
+	firstZip := strings.TrimSuffix(first, ".log") + ".zip"
+
+	// 手动轮转, 超出 MaxFiles 的压缩文件被删除
+	fakeCurrentTime = t2
+	isNil(l.Rotate(), t)
+	second := backupFile(dir)
+	secondZip := strings.TrimSuffix(second, ".log") + ".zip"
+	isNil(l.Close(), t)
+
+	var got []Event
+	for e := range events {
+		got = append(got, e)
+	}
+
+	want := []Event{
+		{Type: EventOpened, Path: logFile(dir), Time: t0},
+		{Type: EventOpened, Path: logFile(dir), Time: t1},
+		{Type: EventRotated, Path: logFile(dir), BackupPath: first, Reason: RotateReasonSize, Time: t1},
+		{Type: EventCompressStarted, Path: first, BackupPath: firstZip, Time: t1},
+		{Type: EventCompressFinished, Path: first, BackupPath: firstZip, Time: t1},
+		{Type: EventOpened, Path: logFile(dir), Time: t2},
+		{Type: EventRotated, Path: logFile(dir), BackupPath: second, Reason: RotateReasonManual, Time: t2},
+		{Type: EventRemoved, Path: firstZip, Time: t2},
+		{Type: EventCompressStarted, Path: second, BackupPath: secondZip, Time: t2},
+		{Type: EventCompressFinished, Path: second, BackupPath: secondZip, Time: t2},
+		{Type: EventClosed, Path: logFile(dir), Time: t2},
+	}
+	equals(len(want), len(got), t)
+	for i := range want {
+		equals(want[i], got[i], t)
+	}
+	equals(int64(0), l.DroppedEvents(), t)
+}
+
+// TestEvents_Dropped 测试缓冲区已满时丢弃事件而不阻塞, 关闭后才调用 Events 时返回已关闭的通道
+func TestEvents_Dropped(t *testing.T) {
+	dir := makeTempDir("TestEvents_Dropped", t)
+	defer func() { _ = os.RemoveAll(dir) }()
+
+	l := &LogRotateX{LogFilePath: logFile(dir)}
+	events := l.Events()
+	for i := 0; i < eventBufferSize+10; i++ {
+		l.sendEvent(Event{Type: EventCleanupRerun})
+	}
+	equals(int64(10), l.DroppedEvents(), t)
+	isNil(l.Close(), t)
+
+	// 关闭事件同样因缓冲区已满被丢弃
+	equals(int64(11), l.Stats().DroppedEvents, t)
+	n := 0
+	for range events {
+		n++
+	}
+	equals(eventBufferSize, n, t)
+
+	closed := &LogRotateX{LogFilePath: logFile(dir)}
+	isNil(closed.Close(), t)
+	if _, ok := <-closed.Events(); ok {
+		t.Fatal("期望关闭后返回已关闭的通道")
+	}
+}
@@ -17,14 +17,33 @@ import (
 
 // scanConfig 日志文件扫描配置
 type scanConfig struct {
-	prefix        string             // 日志文件前缀
-	ext           string             // 日志文件扩展名
-	compressedExt string             // 压缩文件扩展名
-	timestampSet  map[time.Time]bool // 时间戳去重集合 (nil 表示不检查)
+	base           string             // 当前日志文件名 (不包含目录)
+	prefix         string             // 日志文件前缀
+	ext            string             // 日志文件扩展名
+	compressSuffix string             // 压缩文件后缀
+	namer          Namer              // 备份文件的命名规则
+	numbered       bool               // 是否为编号备份模式
+	pattern        *pathPattern       // 文件名模式, 未启用时为 nil
+	timestampSet   map[backupKey]bool // 时间戳去重集合 (nil 表示不检查)
+}
+
+// backupKey 是备份文件的去重键, 由时间戳和序号组成, 编号备份模式下只使用编号
+type backupKey struct {
+	timestamp time.Time
+	seq       int
+	index     int
+}
+
+// key 返回日志文件的去重键
+func (f logInfo) key() backupKey {
+	if f.index > 0 {
+		return backupKey{index: f.index}
+	}
+	return backupKey{timestamp: f.timestamp, seq: f.seq}
 }
 
 // cleanupSync 同步执行日志文件的压缩和清理操作。
-// 根据 MaxBackups、MaxAge 和 Compress 配置处理旧日志文件。
+// 根据 MaxBackups、MaxAge、MaxTotalSize、Compress 和剩余空间下限配置处理旧日志文件。
 //
 // 返回值:
 //   - error: 操作失败时返回错误，否则返回 nil
@@ -35,10 +54,19 @@ func (l *LogRotateX) cleanupSync() error {
 	}
 
 	// 快速路径: 如果没有设置保留数量, 保留天数, 且不启用压缩, 则直接返回
-	if l.MaxFiles <= 0 && l.MaxAge <= 0 && !l.Compress {
+	if l.MaxFiles <= 0 && l.MaxAge <= 0 && l.MaxTotalSize <= 0 && !l.Compress && !l.freeSpaceEnabled() {
 		return nil
 	}
 
+	// 多进程模式: 持有清理锁, 避免多个进程同时压缩或删除同一批文件
+	unlock, err := l.lockCleanup()
+	if err != nil {
+		return err
+	}
+	defer unlock()
+	l.stats.cleanupRuns.Add(1)
+	defer l.stats.recordCleanup(currentTime())
+
 	// 获取所有旧的日志文件信息 (按时间戳降序排列)
 	files, err := l.oldLogFiles()
 	if err != nil {
@@ -62,7 +90,17 @@ func (l *LogRotateX) cleanupSync() error {
 	}
 
 	// 执行清理操作
-	return l.executeCleanup(remove, compress)
+	if err := l.executeCleanup(remove, compress); err != nil {
+		return err
+	}
+
+	// 按压缩后的实际大小执行总大小配额
+	if err := l.enforceTotalSize(); err != nil {
+		return err
+	}
+
+	// 剩余空间保护
+	return l.enforceFreeSpace()
 }
 
 // executeCleanup 执行文件删除和压缩操作
@@ -80,7 +118,7 @@ func (l *LogRotateX) executeCleanup(remove, compress []logInfo) error {
 	}
 
 	// 收集所有错误
-	var errors []error
+	var errs cleanupErrors
 
 	// 执行文件移除操作
 	if len(remove) > 0 {
@@ -90,8 +128,12 @@ func (l *LogRotateX) executeCleanup(remove, compress []logInfo) error {
 
 			// 移除文件
 			if err := os.Remove(filePath); err != nil {
-				errors = append(errors, fmt.Errorf("failed to remove log file %s: %w", filePath, err))
+				errs = append(errs, &OpError{Op: OpRemove, Path: filePath, Err: err})
+				continue
 			}
+			l.stats.removals.Add(1)
+			l.sendEvent(Event{Type: EventRemoved, Path: filePath})
+			l.emit(l.OnRemove, FileEvent{OldPath: filePath, BackupTime: f.timestamp})
 		}
 	}
 
@@ -100,8 +142,9 @@ func (l *LogRotateX) executeCleanup(remove, compress []logInfo) error {
 		for _, f := range compress {
 			// 获取文件的完整路径
 			filePath := l.getFilePath(f)
-			// 基础文件名（不包含扩展名）
-			baseName := strings.TrimSuffix(f.Name(), filepath.Ext(f.Name()))
+			// 基础文件名（去掉日志文件扩展名, 时间戳中可能包含点号, 不能使用 filepath.Ext）
+			_, ext := l.prefixAndExt()
+			baseName := strings.TrimSuffix(f.Name(), ext)
 			// 压缩文件路径, 格式: 父目录/基础文件名.压缩类型
 			compressPath := filepath.Join(filepath.Dir(filePath), baseName+l.CompressType.String())
 
@@ -115,34 +158,62 @@ func (l *LogRotateX) executeCleanup(remove, compress []logInfo) error {
 			}
 
 			// 压缩文件
+			start := currentTime()
+			l.sendEvent(Event{Type: EventCompressStarted, Path: filePath, BackupPath: compressPath, Time: start})
 			if err := comprx.PackOptions(compressPath, filePath, opts); err != nil {
-				errors = append(errors, fmt.Errorf("failed to compress log file %s: %w", filePath, err))
+				errs = append(errs, &OpError{Op: OpCompress, Path: filePath, Err: err})
+				l.sendEvent(Event{Type: EventCompressFinished, Path: filePath, BackupPath: compressPath, Err: err})
 				continue // 压缩失败就跳过，保留原文件
 			}
+			var compressedSize int64
+			if info, err := os.Stat(compressPath); err == nil {
+				compressedSize = info.Size()
+			}
+			l.stats.recordCompression(currentTime().Sub(start), f.Size(), compressedSize)
 
 			// 删除原文件
 			if err := os.Remove(filePath); err != nil {
-				errors = append(errors, fmt.Errorf("failed to delete original file %s: %w", filePath, err))
+				errs = append(errs, &OpError{Op: OpRemove, Path: filePath, Err: fmt.Errorf("failed to delete original file after compression: %w", err)})
 			}
+			l.sendEvent(Event{Type: EventCompressFinished, Path: filePath, BackupPath: compressPath})
+			l.emit(l.OnCompress, FileEvent{OldPath: filePath, NewPath: compressPath, BackupTime: f.timestamp})
 		}
 	}
 
 	// 清理空日期目录
 	l.cleanupEmptyDirs()
 
+	// 压缩或删除后重新指向最新的备份文件
+	if len(remove) > 0 || len(compress) > 0 {
+		l.updateBackupLink()
+	}
+
 	// 如果有错误，返回聚合错误
-	if len(errors) > 0 {
-		var errMsg strings.Builder
-		errMsg.WriteString("multiple errors occurred during cleanup execution:\n")
-		for i, err := range errors {
-			errMsg.WriteString(fmt.Sprintf("  %d. %v\n", i+1, err))
-		}
-		return fmt.Errorf("%s", errMsg.String())
+	if len(errs) > 0 {
+		return errs
 	}
 
 	return nil
 }
 
+// cleanupErrors 是一次清理中发生的多个错误, 每个错误都是 *OpError, 可以通过 errors.As 逐个获取
+type cleanupErrors []error
+
+// Error 实现 error 接口, 按顺序列出所有错误
+func (e cleanupErrors) Error() string {
+	var errMsg strings.Builder
+	errMsg.WriteString("multiple errors occurred during cleanup execution:\n")
+	for i, err := range e {
+		errMsg.WriteString(fmt.Sprintf("  %d. %v\n", i+1, err))
+	}
+	return errMsg.String()
+}
+
+// Unwrap 返回所有错误, 供 errors.Is 和 errors.As 使用
+func (e cleanupErrors) Unwrap() []error {
+	return e
+}
+
 // getFilePath 获取日志文件的完整路径
 // 支持日期目录模式和传统模式
 //
@@ -152,8 +223,8 @@ func (l *LogRotateX) executeCleanup(remove, compress []logInfo) error {
 // 返回值:
 //   - string: 文件的完整路径
 func (l *LogRotateX) getFilePath(f logInfo) string {
-	// 如果是日期目录模式，文件路径需要包含日期目录
-	if l.DateDirLayout {
+	// 如果是日期目录模式，文件路径需要包含日期目录 (编号备份始终位于日志目录)
+	if l.useDateDir() {
 		// 从文件名中解析日期
 		timestamp := f.timestamp
 		dateDir := timestamp.Format("2006-01-02")
@@ -167,7 +238,7 @@ func (l *LogRotateX) getFilePath(f logInfo) string {
 // 当某个日期目录下的所有文件都被删除后，删除该空目录
 func (l *LogRotateX) cleanupEmptyDirs() {
 	// 如果未启用日期目录模式，直接返回
-	if !l.DateDirLayout {
+	if !l.useDateDir() {
 		return
 	}
 
@@ -206,7 +277,7 @@ func (l *LogRotateX) cleanupAsync() {
 	}
 
 	// 快速路径: 无需清理直接返回
-	if l.MaxFiles <= 0 && l.MaxAge <= 0 && !l.Compress {
+	if l.MaxFiles <= 0 && l.MaxAge <= 0 && l.MaxTotalSize <= 0 && !l.Compress && !l.freeSpaceEnabled() {
 		return
 	}
 
@@ -222,12 +293,12 @@ func (l *LogRotateX) cleanupAsync() {
 	_ = l.rerunNeeded.CompareAndSwap(false, true)
 }
 
-// 单协程清理循环: 每轮都现查现算，错误仅打印
+// 单协程清理循环: 每轮都现查现算，错误通过 ErrorHandler 报告
 func (l *LogRotateX) runCleanupLoop() {
 	defer func() {
 		// panic 保护，防止 wg 和 running 状态失配
 		if r := recover(); r != nil {
-			fmt.Printf("panic in async cleanup: %v\n", r)
+			l.reportError(OpCleanup, l.dir(), fmt.Errorf("panic in async cleanup: %v", r))
 		}
 
 		l.cleanupRunning.Store(false) // 退出时重置运行状态
@@ -239,10 +310,21 @@ func (l *LogRotateX) runCleanupLoop() {
 			return
 		}
 
+		// 多进程模式: 每轮清理前持有清理锁
+		unlock, err := l.lockCleanup()
+		if err != nil {
+			l.reportError(OpCleanup, l.dir(), fmt.Errorf("failed to acquire cleanup lock: %w", err))
+			break
+		}
+		l.stats.cleanupRuns.Add(1)
+		start := currentTime()
+
 		// 1) 最新文件状态
 		files, err := l.oldLogFiles()
 		if err != nil {
-			fmt.Printf("failed to get old log files: %v\n", err)
+			unlock()
+			l.stats.recordCleanup(start)
+			l.reportError(OpCleanup, l.dir(), fmt.Errorf("failed to get old log files: %w", err))
 
 			// 如果没有新的触发需求，直接退出循环，避免空转
 			if !l.rerunNeeded.Load() {
@@ -275,11 +357,23 @@ func (l *LogRotateX) runCleanupLoop() {
 
 		// 4) 执行清理
 		if err := l.executeCleanup(remove, compress); err != nil {
-			fmt.Printf("async cleanup error: %v\n", err)
+			l.reportError(OpCleanup, l.dir(), err)
 		}
 
-		// 5) 是否重跑 (合并触发: 多次触发只续跑一轮)
+		// 5) 总大小配额与剩余空间保护
+		if err := l.enforceTotalSize(); err != nil {
+			l.reportError(OpCleanup, l.dir(), err)
+		}
+		if err := l.enforceFreeSpace(); err != nil {
+			l.reportError(OpCleanup, l.dir(), err)
+		}
+		unlock()
+		l.stats.recordCleanup(start)
+
+		// 6) 是否重跑 (合并触发: 多次触发只续跑一轮)
 		if l.rerunNeeded.Swap(false) {
+			l.stats.cleanupReruns.Add(1)
+			l.sendEvent(Event{Type: EventCleanupRerun, Path: l.dir()})
 			continue
 		}
 		break
@@ -298,29 +392,49 @@ func (l *LogRotateX) runCleanupLoop() {
 func (l *LogRotateX) processLogFile(f os.DirEntry, cfg scanConfig) (logInfo, bool) {
 	fileName := f.Name()
 
-	// 快速前缀检查
-	if cfg.prefix != "" && !strings.HasPrefix(fileName, cfg.prefix) {
+	// 跳过符号链接 (如 LinkName、BackupLinkName 指向的链接)
+	if f.Type()&os.ModeSymlink != 0 {
 		return logInfo{}, false
 	}
 
-	// 确定文件类型和扩展名
-	var targetExt string
-	if strings.HasSuffix(fileName, cfg.compressedExt) {
-		targetExt = cfg.compressedExt
-	} else if strings.HasSuffix(fileName, cfg.ext) {
-		targetExt = cfg.ext
-	} else {
+	// 编号备份: 按编号识别, 时间戳使用文件的修改时间
+	if cfg.numbered {
+		index, _, ok := parseBackupIndex(fileName, cfg.base, cfg.compressSuffix)
+		if !ok || (cfg.timestampSet != nil && cfg.timestampSet[backupKey{index: index}]) {
+			return logInfo{}, false
+		}
+		info, err := f.Info()
+		if err != nil {
+			return logInfo{}, false
+		}
+		return logInfo{timestamp: info.ModTime(), index: index, FileInfo: info}, true
+	}
+
+	// 快速前缀检查 (文件名模式下不同时间的文件前缀不同)
+	if cfg.pattern == nil && cfg.prefix != "" && !strings.HasPrefix(fileName, cfg.prefix) {
 		return logInfo{}, false
 	}
 
-	// 解析时间戳
-	timestamp, parseErr := l.fastTimeFromName(fileName, cfg.prefix, targetExt)
-	if parseErr != nil {
+	// 压缩文件还原为压缩前的备份文件名 (支持 name_ts.zip 和 name_ts.log.zip 两种形式)
+	name := fileName
+	if trimmed, ok := strings.CutSuffix(fileName, cfg.compressSuffix); ok && cfg.compressSuffix != "" {
+		name = trimmed
+		if !strings.HasSuffix(name, cfg.ext) {
+			name += cfg.ext
+		}
+	}
+	if !strings.HasSuffix(name, cfg.ext) {
+		return logInfo{}, false
+	}
+
+	// 按命名规则解析时间戳, 并确认文件名可由同一规则还原, 排除前缀相同的其他文件
+	timestamp, seq, ok := l.parseBackupName(name, cfg)
+	if !ok {
 		return logInfo{}, false
 	}
 
 	// 检查时间戳重复 (如果配置了 timestampSet)
-	if cfg.timestampSet != nil && cfg.timestampSet[timestamp] {
+	if cfg.timestampSet != nil && cfg.timestampSet[backupKey{timestamp: timestamp, seq: seq}] {
 		return logInfo{}, false
 	}
 
@@ -330,7 +444,41 @@ func (l *LogRotateX) processLogFile(f os.DirEntry, cfg scanConfig) (logInfo, boo
 		return logInfo{}, false
 	}
 
-	return logInfo{timestamp, info}, true
+	return logInfo{timestamp: timestamp, seq: seq, FileInfo: info}, true
+}
+
+// parseBackupName 按命名规则解析备份文件名中的时间戳和序号, 并确认文件名可由同一规则还原。
+// 文件名模式下, 匹配模式的文件 (之前时间段的日志文件) 使用模式中的时间,
+// 按大小轮转生成的备份文件以其时间对应的模式文件名作为基础名称还原。
+//
+// 参数:
+//   - name: 备份文件名 (不包含目录和压缩后缀)
+//   - cfg: 扫描配置
+//
+// 返回值:
+//   - time.Time: 备份时间
+//   - int: 同一时间戳内的序号
+//   - bool: 不是备份文件时返回 false
+func (l *LogRotateX) parseBackupName(name string, cfg scanConfig) (time.Time, int, bool) {
+	if cfg.pattern != nil {
+		if t, ok := cfg.pattern.parse(name); ok {
+			return t, 0, true
+		}
+	}
+
+	t, seq, ok := cfg.namer.Parse(name)
+	if !ok {
+		return time.Time{}, 0, false
+	}
+
+	base := cfg.base
+	if cfg.pattern != nil {
+		base = cfg.pattern.formatBase(t)
+	}
+	if cfg.namer.Name(base, t, seq) != name {
+		return time.Time{}, 0, false
+	}
+	return t, seq, true
 }
 
 // oldLogFiles 返回当前目录中的所有备份日志文件，按时间戳排序。
@@ -354,7 +502,6 @@ func (l *LogRotateX) oldLogFiles() ([]logInfo, error) {
 	// 获取日志文件的前缀和扩展名 (只计算一次)
 	prefix, ext := l.prefixAndExt()
 	currentFileName := filepath.Base(l.filename())
-	compressedExt := ext + l.CompressType.String()
 
 	// 预估容量，避免频繁扩容
 	estimatedCapacity := len(files) / 4
@@ -363,14 +510,18 @@ func (l *LogRotateX) oldLogFiles() ([]logInfo, error) {
 	}
 
 	logFiles := make([]logInfo, 0, estimatedCapacity)
-	timestampSet := make(map[time.Time]bool, estimatedCapacity)
+	timestampSet := make(map[backupKey]bool, estimatedCapacity)
 
 	// 创建扫描配置
 	cfg := scanConfig{
-		prefix:        prefix,
-		ext:           ext,
-		compressedExt: compressedExt,
-		timestampSet:  timestampSet,
+		base:           currentFileName,
+		prefix:         prefix,
+		ext:            ext,
+		compressSuffix: l.CompressType.String(),
+		namer:          l.namer(),
+		numbered:       l.NumberedBackups,
+		pattern:        l.pattern,
+		timestampSet:   timestampSet,
 	}
 
 	// 扫描根目录和日期目录
@@ -381,6 +532,10 @@ func (l *LogRotateX) oldLogFiles() ([]logInfo, error) {
 		}
 
 		if f.IsDir() {
+			// 编号备份和文件名模式不使用日期目录
+			if cfg.numbered || cfg.pattern != nil {
+				continue
+			}
 			// 扫描日期目录
 			dirPath := filepath.Join(l.dir(), f.Name())
 			dirFiles, err := l.scanDateDir(dirPath, cfg)
@@ -390,13 +545,13 @@ func (l *LogRotateX) oldLogFiles() ([]logInfo, error) {
 			// 合并结果
 			for _, df := range dirFiles {
 				logFiles = append(logFiles, df)
-				timestampSet[df.timestamp] = true
+				timestampSet[df.key()] = true
 			}
 		} else {
 			// 处理根目录文件 (支持混合模式)
 			if logInfo, ok := l.processLogFile(f, cfg); ok {
 				logFiles = append(logFiles, logInfo)
-				timestampSet[logInfo.timestamp] = true
+				timestampSet[logInfo.key()] = true
 			}
 		}
 	}
@@ -521,9 +676,7 @@ func (l *LogRotateX) keepByDaysAndCount(files []logInfo, maxAge, maxBackups int)
 	var keep []logInfo
 	for _, dayFiles := range dayGroups {
 		// 对每天的文件按时间排序 (从新到旧)
-		sort.Slice(dayFiles, func(i, j int) bool {
-			return dayFiles[i].timestamp.After(dayFiles[j].timestamp)
-		})
+		sort.Sort(byFormatTime(dayFiles))
 
 		// 每天保留最新的maxBackups个文件
 		keepCount := maxBackups
@@ -568,48 +721,6 @@ func (l *LogRotateX) calculateRemoveList(allFiles, keepFiles []logInfo) []logInf
 	return remove
 }
 
-// fastTimeFromName 从文件名中快速解析时间戳 (纯数字格式优化版) 。
-func (l *LogRotateX) fastTimeFromName(filename, prefix, ext string) (time.Time, error) {
-	// 计算时间戳的起始和结束位置
-	var startPos, endPos int
-
-	if prefix == "" {
-		startPos = 0
-		endPos = len(filename) - len(ext)
-	} else {
-		startPos = len(prefix) + 1 // 跳过前缀和分隔符 "_"
-		endPos = len(filename) - len(ext)
-	}
-
-	// 增强的边界检查
-	if startPos < 0 || endPos > len(filename) || startPos >= endPos {
-		return time.Time{}, fmt.Errorf("invalid filename format: %s", filename)
-	}
-
-	// 检查时间戳长度
-	timestampLen := endPos - startPos
-	if timestampLen != expectedTimestampLen {
-		return time.Time{}, fmt.Errorf("invalid timestamp length %d, expected %d in file: %s",
-			timestampLen, expectedTimestampLen, filename)
-	}
-
-	// 提取时间戳字符串
-	timestampStr := filename[startPos:endPos]
-
-	// 快速验证: 确保都是数字
-	if !isAllDigits(timestampStr) {
-		return time.Time{}, fmt.Errorf("timestamp contains non-digit characters: %s", timestampStr)
-	}
-
-	// 解析时间戳
-	t, err := time.Parse(backupTimeFormat, timestampStr)
-	if err != nil {
-		return time.Time{}, fmt.Errorf("failed to parse timestamp %s: %w", timestampStr, err)
-	}
-
-	return t, nil
-}
-
 // isAllDigits 快速检查字符串是否全为数字
 func isAllDigits(s string) bool {
 	// 空字符串被认为不是全数字
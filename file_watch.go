@@ -0,0 +1,26 @@
+// file_watch.go 实现了logrotatex包的日志文件监视功能。
+// 当日志文件被外部工具 (如系统 logrotate) 移动、截断或删除时，
+// 通过节流的 Stat 检查发现差异，并重新打开日志文件继续写入。
+
+package logrotatex
+
+import (
+	"time"
+)
+
+// watchFile 在监视模式下按 WatchInterval 节流检查日志文件状态。
+// 检测到文件被移动或删除时关闭当前句柄, 由 Write 通过 openExistingOrNew 重新打开
+// (openNew 会在目录被删除时重新创建目录); 检测到文件被截断时以磁盘上的大小为准。
+//
+// 返回值:
+//   - error: 检查失败时返回错误, 否则返回 nil
+func (l *LogRotateX) watchFile() error {
+	// 使用单调时钟节流, 避免每次写入都产生 Stat 系统调用
+	now := time.Now()
+	if !l.lastWatch.IsZero() && now.Sub(l.lastWatch) < l.WatchInterval {
+		return nil
+	}
+	l.lastWatch = now
+
+	return l.syncWithDisk()
+}
@@ -0,0 +1,99 @@
+// file_watch_test.go 包含了日志文件监视模式 (WatchFile) 的测试用例。
+// 该文件模拟外部工具移动、删除、截断日志文件的场景，验证 LogRotateX 能够
+// 及时发现差异并重新打开文件，以及检查节流是否生效。
+
+package logrotatex
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// TestWatchFile_ExternalMove 测试日志文件被外部移动后会重新创建并写入新文件
+func TestWatchFile_ExternalMove(t *testing.T) {
+	dir := makeTempDir("TestWatchFile_ExternalMove", t)
+	defer func() { _ = os.RemoveAll(dir) }()
+
+	l := &LogRotateX{LogFilePath: logFile(dir), WatchFile: true, WatchInterval: time.Nanosecond}
+	defer func() { _ = l.Close() }()
+
+	_, err := l.Write([]byte("before\n"))
+	isNil(err, t)
+
+	// 模拟系统 logrotate 移走日志文件
+	moved := filepath.Join(dir, "foobar.log.1")
+	isNil(os.Rename(logFile(dir), moved), t)
+
+	_, err = l.Write([]byte("after\n"))
+	isNil(err, t)
+
+	existsWithContent(moved, []byte("before\n"), t)
+	existsWithContent(logFile(dir), []byte("after\n"), t)
+}
+
+// TestWatchFile_DirectoryDeleted 测试日志目录被删除后会重新创建目录和文件
+func TestWatchFile_DirectoryDeleted(t *testing.T) {
+	dir := makeTempDir("TestWatchFile_DirectoryDeleted", t)
+	defer func() { _ = os.RemoveAll(dir) }()
+
+	sub := filepath.Join(dir, "sub")
+	filename := filepath.Join(sub, "app.log")
+	l := &LogRotateX{LogFilePath: filename, WatchFile: true, WatchInterval: time.Nanosecond}
+	defer func() { _ = l.Close() }()
+
+	_, err := l.Write([]byte("before\n"))
+	isNil(err, t)
+
+	isNil(os.RemoveAll(sub), t)
+
+	_, err = l.Write([]byte("after\n"))
+	isNil(err, t)
+	existsWithContent(filename, []byte("after\n"), t)
+}
+
+// TestWatchFile_Truncated 测试日志文件被外部截断后会同步文件大小, 避免提前轮转
+func TestWatchFile_Truncated(t *testing.T) {
+	dir := makeTempDir("TestWatchFile_Truncated", t)
+	defer func() { _ = os.RemoveAll(dir) }()
+
+	l := &LogRotateX{LogFilePath: logFile(dir), MaxSize: 1, WatchFile: true, WatchInterval: time.Nanosecond}
+	defer func() { _ = l.Close() }()
+
+	big := make([]byte, megabyte-100)
+	_, err := l.Write(big)
+	isNil(err, t)
+
+	// 外部截断文件
+	isNil(os.Truncate(logFile(dir), 0), t)
+
+	// 若未同步大小, 这次写入会触发轮转
+	b := make([]byte, 200)
+	_, err = l.Write(b)
+	isNil(err, t)
+
+	fileCount(dir, 1, t)
+	existsWithContent(logFile(dir), b, t)
+}
+
+// TestWatchFile_Throttled 测试检查间隔内不会重复检查文件状态
+func TestWatchFile_Throttled(t *testing.T) {
+	dir := makeTempDir("TestWatchFile_Throttled", t)
+	defer func() { _ = os.RemoveAll(dir) }()
+
+	l := &LogRotateX{LogFilePath: logFile(dir), WatchFile: true, WatchInterval: time.Hour}
+	defer func() { _ = l.Close() }()
+
+	_, err := l.Write([]byte("before\n"))
+	isNil(err, t)
+
+	moved := filepath.Join(dir, "foobar.log.1")
+	isNil(os.Rename(logFile(dir), moved), t)
+
+	// 间隔未到, 仍写入已被移动的文件
+	_, err = l.Write([]byte("after\n"))
+	isNil(err, t)
+	existsWithContent(moved, []byte("before\nafter\n"), t)
+	notExist(logFile(dir), t)
+}
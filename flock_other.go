@@ -0,0 +1,21 @@
+// flock_other.go 为不支持文件锁的平台提供占位实现。
+// 在这些平台上启用多进程模式时，加锁会返回 errors.ErrUnsupported。
+//go:build !linux && !darwin && !freebsd && !netbsd && !openbsd && !dragonfly && !windows
+// +build !linux,!darwin,!freebsd,!netbsd,!openbsd,!dragonfly,!windows
+
+package logrotatex
+
+import (
+	"errors"
+	"os"
+)
+
+// lockFile 在当前平台不受支持。
+func lockFile(_ *os.File) error {
+	return errors.ErrUnsupported
+}
+
+// unlockFile 在当前平台不受支持。
+func unlockFile(_ *os.File) error {
+	return errors.ErrUnsupported
+}
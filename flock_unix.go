@@ -0,0 +1,27 @@
+// flock_unix.go 实现了类Unix系统下的文件建议锁。
+// 该文件通过 flock 系统调用对锁文件加排他锁，用于多进程模式下的轮转和清理互斥。
+//go:build linux || darwin || freebsd || netbsd || openbsd || dragonfly
+// +build linux darwin freebsd netbsd openbsd dragonfly
+
+package logrotatex
+
+import (
+	"os"
+	"syscall"
+)
+
+// lockFile 对文件加排他建议锁, 阻塞直到获取成功。
+func lockFile(f *os.File) error {
+	for {
+		err := syscall.Flock(int(f.Fd()), syscall.LOCK_EX)
+		// 被信号中断时重试
+		if err != syscall.EINTR {
+			return err
+		}
+	}
+}
+
+// unlockFile 释放文件上的建议锁。
+func unlockFile(f *os.File) error {
+	return syscall.Flock(int(f.Fd()), syscall.LOCK_UN)
+}
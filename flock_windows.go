@@ -0,0 +1,42 @@
+// flock_windows.go 实现了Windows系统下的文件锁。
+// 该文件通过 kernel32 的 LockFileEx/UnlockFileEx 对锁文件的首字节加排他锁，
+// 语义上与类Unix系统的 flock 排他锁一致。
+//go:build windows
+// +build windows
+
+package logrotatex
+
+import (
+	"os"
+	"syscall"
+	"unsafe"
+)
+
+var (
+	modkernel32      = syscall.NewLazyDLL("kernel32.dll")
+	procLockFileEx   = modkernel32.NewProc("LockFileEx")
+	procUnlockFileEx = modkernel32.NewProc("UnlockFileEx")
+)
+
+// lockfileExclusiveLock 对应 LOCKFILE_EXCLUSIVE_LOCK 标志
+const lockfileExclusiveLock = 0x00000002
+
+// lockFile 对文件加排他锁, 阻塞直到获取成功。
+func lockFile(f *os.File) error {
+	ol := new(syscall.Overlapped)
+	r1, _, err := procLockFileEx.Call(f.Fd(), lockfileExclusiveLock, 0, 1, 0, uintptr(unsafe.Pointer(ol)))
+	if r1 == 0 {
+		return err
+	}
+	return nil
+}
+
+// unlockFile 释放文件上的锁。
+func unlockFile(f *os.File) error {
+	ol := new(syscall.Overlapped)
+	r1, _, err := procUnlockFileEx.Call(f.Fd(), 0, 1, 0, uintptr(unsafe.Pointer(ol)))
+	if r1 == 0 {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,107 @@
+// freespace.go 实现了logrotatex包的剩余空间保护功能。
+// 设置 MinFreeSpace 或 MinFreePercent 后，按 FreeSpaceCheckInterval 节流检查日志所在文件系统的
+// 剩余空间 (轮转后的清理中也会检查)，低于下限时从最旧的备份文件开始删除，避免写入因磁盘写满而失败。
+
+package logrotatex
+
+import (
+	"fmt"
+	"time"
+)
+
+// diskStats 是文件系统的空间信息
+type diskStats struct {
+	total uint64 // total 是文件系统的总容量 (字节)
+	avail uint64 // avail 是当前用户可用的剩余空间 (字节)
+}
+
+// statFS 返回 path 所在文件系统的空间信息, 测试中替换为模拟实现
+var statFS = diskUsage
+
+// freeSpaceEnabled 检查是否启用了剩余空间保护
+func (l *LogRotateX) freeSpaceEnabled() bool {
+	return l.MinFreeSpace > 0 || l.MinFreePercent > 0
+}
+
+// spaceDeficit 返回剩余空间距离下限还差多少字节, 未低于下限时返回值小于等于 0。
+// MinFreeSpace 与 MinFreePercent 同时设置时取较大的下限。
+//
+// 返回值:
+//   - int64: 需要释放的字节数
+//   - error: 查询文件系统失败时返回错误
+func (l *LogRotateX) spaceDeficit() (int64, error) {
+	st, err := statFS(l.dir())
+	if err != nil {
+		return 0, err
+	}
+
+	floor := int64(l.MinFreeSpace)
+	if l.MinFreePercent > 0 {
+		if v := int64(float64(st.total) * l.MinFreePercent / 100); v > floor {
+			floor = v
+		}
+	}
+	return floor - int64(st.avail), nil
+}
+
+// checkFreeSpace 在 Write 中按 FreeSpaceCheckInterval 节流检查剩余空间, 低于下限时按 Async 配置触发清理。
+// 查询失败时不影响写入, 错误由清理时的检查返回。
+func (l *LogRotateX) checkFreeSpace() {
+	// 使用单调时钟节流, 避免每次写入都产生 statfs 系统调用
+	now := time.Now()
+	if !l.lastSpaceCheck.IsZero() && now.Sub(l.lastSpaceCheck) < l.FreeSpaceCheckInterval {
+		return
+	}
+	l.lastSpaceCheck = now
+
+	if deficit, err := l.spaceDeficit(); err != nil || deficit <= 0 {
+		return
+	}
+
+	if l.Async {
+		l.cleanupAsync()
+	} else if err := l.cleanupSync(); err != nil {
+		l.reportError(OpCleanup, l.dir(), err)
+	}
+}
+
+// enforceFreeSpace 剩余空间低于下限时从最旧的备份文件开始删除, 直到释放的空间足以回到下限之上。
+// 在按数量、天数、总大小清理之后调用, 调用方需持有清理锁。
+//
+// 返回值:
+//   - error: 查询或删除失败时返回错误; 发生删除时也返回错误, 通过清理的错误路径报告被删除的文件数量
+func (l *LogRotateX) enforceFreeSpace() error {
+	if !l.freeSpaceEnabled() {
+		return nil
+	}
+
+	deficit, err := l.spaceDeficit()
+	if err != nil {
+		return &OpError{Op: OpFreeSpace, Path: l.dir(), Err: fmt.Errorf("failed to check free space: %w", err)}
+	}
+	if deficit <= 0 {
+		return nil
+	}
+
+	files, err := l.oldLogFiles()
+	if err != nil {
+		return fmt.Errorf("failed to get old log files: %w", err)
+	}
+	if len(files) == 0 {
+		return &OpError{Op: OpFreeSpace, Path: l.dir(), Err: fmt.Errorf("free space is %s below minimum and no backup files left to remove", ByteSize(deficit))}
+	}
+
+	// 从最旧的文件开始删除 (files 按时间从新到旧排列)
+	var remove []logInfo
+	var freed int64
+	for i := len(files) - 1; i >= 0 && freed < deficit; i-- {
+		remove = append(remove, files[i])
+		freed += files[i].Size()
+	}
+
+	if err := l.executeCleanup(remove, nil); err != nil {
+		return err
+	}
+	return &OpError{Op: OpFreeSpace, Path: l.dir(), Err: fmt.Errorf("free space was %s below minimum, removed %d oldest backup files (%s)",
+		ByteSize(deficit), len(remove), ByteSize(freed))}
+}
@@ -0,0 +1,123 @@
+// freespace_test.go 包含了剩余空间保护 (MinFreeSpace/MinFreePercent) 的测试用例。
+// 该文件通过模拟的文件系统空间查询验证低于下限时删除最旧的备份文件，
+// 删除通过清理错误路径报告，以及写入路径中的节流检查。
+
+package logrotatex
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// fakeStatFS 返回固定空间信息的模拟查询函数, 可用空间随已删除的备份文件增加
+func fakeStatFS(total, avail uint64, dir string) func(string) (diskStats, error) {
+	return func(string) (diskStats, error) {
+		free := avail
+		entries, _ := os.ReadDir(dir)
+		// 每少一个文件 (初始为 4 个) 视为释放 100 字节
+		if n := len(entries); n < 4 {
+			free += uint64(4-n) * 100
+		}
+		return diskStats{total: total, avail: free}, nil
+	}
+}
+
+// writeBackups 在目录中创建指定的备份文件, 每个 100 字节
+func writeBackups(t *testing.T, dir string, names ...string) {
+	t.Helper()
+	for _, name := range names {
+		isNil(os.WriteFile(filepath.Join(dir, name), make([]byte, 100), 0600), t)
+	}
+}
+
+// TestMinFreeSpace_EvictsOldest 测试剩余空间低于字节下限时从最旧的备份文件开始删除, 并报告删除
+func TestMinFreeSpace_EvictsOldest(t *testing.T) {
+	dir := makeTempDir("TestMinFreeSpace_EvictsOldest", t)
+	defer func() { _ = os.RemoveAll(dir) }()
+
+	writeBackups(t, dir, "foobar_20261014090000.log", "foobar_20261015090000.log", "foobar_20261016090000.log")
+
+	l := &LogRotateX{LogFilePath: logFile(dir)}
+	defer func() { _ = l.Close() }()
+	_, err := l.Write([]byte("data"))
+	isNil(err, t)
+
+	originalStatFS := statFS
+	defer func() { statFS = originalStatFS }()
+	statFS = fakeStatFS(10000, 850, dir)
+	l.MinFreeSpace = 1000
+
+	// 差 150 字节, 需要删除两个最旧的备份文件
+	err = l.cleanupSync()
+	if err == nil || !strings.Contains(err.Error(), "removed 2 oldest backup files") {
+		t.Fatalf("期望通过清理错误报告删除, 实际: %v", err)
+	}
+	notExist(filepath.Join(dir, "foobar_20261014090000.log"), t)
+	notExist(filepath.Join(dir, "foobar_20261015090000.log"), t)
+	exists(filepath.Join(dir, "foobar_20261016090000.log"), t)
+
+	// 回到下限之上后不再删除
+	isNil(l.cleanupSync(), t)
+	exists(filepath.Join(dir, "foobar_20261016090000.log"), t)
+}
+
+// TestMinFreePercent 测试按百分比下限检查, 与字节下限同时设置时取较大值
+func TestMinFreePercent(t *testing.T) {
+	originalStatFS := statFS
+	defer func() { statFS = originalStatFS }()
+	statFS = func(string) (diskStats, error) {
+		return diskStats{total: 1000, avail: 80}, nil
+	}
+
+	tests := []struct {
+		minFree    ByteSize
+		minPercent float64
+		want       int64
+	}{
+		{0, 10, 20},
+		{50, 10, 20},
+		{300, 10, 220},
+		{0, 5, -30},
+	}
+	for _, tt := range tests {
+		l := &LogRotateX{MinFreeSpace: tt.minFree, MinFreePercent: tt.minPercent}
+		deficit, err := l.spaceDeficit()
+		isNil(err, t)
+		equals(tt.want, deficit, t)
+	}
+}
+
+// TestMinFreeSpace_WriteThrottled 测试写入路径中按间隔节流检查剩余空间
+func TestMinFreeSpace_WriteThrottled(t *testing.T) {
+	dir := makeTempDir("TestMinFreeSpace_WriteThrottled", t)
+	defer func() { _ = os.RemoveAll(dir) }()
+
+	originalStatFS := statFS
+	defer func() { statFS = originalStatFS }()
+	calls := 0
+	avail := uint64(10000)
+	statFS = func(string) (diskStats, error) {
+		calls++
+		return diskStats{total: 100000, avail: avail}, nil
+	}
+
+	l := &LogRotateX{LogFilePath: logFile(dir), MinFreeSpace: 1000, FreeSpaceCheckInterval: time.Hour}
+	defer func() { _ = l.Close() }()
+
+	for i := 0; i < 3; i++ {
+		_, err := l.Write([]byte("data"))
+		isNil(err, t)
+	}
+	equals(1, calls, t)
+
+	// 间隔到期后再次检查, 低于下限时删除备份文件
+	writeBackups(t, dir, "foobar_20261016090000.log")
+	avail = 900
+	l.lastSpaceCheck = time.Now().Add(-2 * time.Hour)
+	_, err := l.Write([]byte("data"))
+	isNil(err, t)
+	notExist(filepath.Join(dir, "foobar_20261016090000.log"), t)
+}
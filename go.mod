@@ -2,7 +2,11 @@ module gitee.com/MM-Q/logrotatex
 
 go 1.25.0
 
-require gitee.com/MM-Q/comprx v0.1.6
+require (
+	gitee.com/MM-Q/comprx v0.1.6
+	github.com/BurntSushi/toml v1.5.0
+	gopkg.in/yaml.v3 v3.0.1
+)
 
 require (
 	gitee.com/MM-Q/go-kit v0.0.13 // indirect
@@ -0,0 +1,90 @@
+// hooks.go 实现了logrotatex包的生命周期回调功能。
+// 轮转、压缩和删除备份文件后调用用户设置的 OnRotate、OnCompress、OnRemove 回调，
+// 回调 (以及 ErrorHandler) 按事件发生的顺序在单独的协程中执行，不持有 l.mu，耗时的回调不会阻塞 Write。
+
+package logrotatex
+
+import (
+	"fmt"
+	"os"
+	"time"
+)
+
+// FileEvent 是生命周期回调的参数
+type FileEvent struct {
+	// OldPath 是操作前的文件路径: 轮转前的日志文件、压缩前的备份文件或被删除的文件
+	OldPath string
+
+	// NewPath 是操作后的文件路径: 轮转生成的备份文件或压缩文件, 删除时为空
+	NewPath string
+
+	// BackupTime 是备份文件对应的时间: 轮转时为备份时间, 清理时为备份文件名中的时间 (编号备份为修改时间),
+	// 编号备份移位时超出 MaxFiles 被删除的文件为空
+	BackupTime time.Time
+
+	// Time 是事件发生的时间
+	Time time.Time
+}
+
+// hooksEnabled 检查是否设置了任意生命周期回调或 ErrorHandler (两者都在回调协程中执行)
+func (l *LogRotateX) hooksEnabled() bool {
+	return l.OnRotate != nil || l.OnCompress != nil || l.OnRemove != nil || l.ErrorHandler != nil
+}
+
+// emit 将一次生命周期回调加入队列 (未设置回调时直接返回)。
+//
+// 参数:
+//   - fn: 回调函数
+//   - event: 回调参数, Time 为空时使用当前时间
+func (l *LogRotateX) emit(fn func(FileEvent), event FileEvent) {
+	if fn == nil {
+		return
+	}
+	if event.Time.IsZero() {
+		event.Time = currentTime()
+	}
+	l.enqueueHook(func() { fn(event) })
+}
+
+// enqueueHook 将一次回调 (生命周期回调或 ErrorHandler) 加入队列, 由单独的回调协程按顺序执行
+func (l *LogRotateX) enqueueHook(call func()) {
+	l.hookMu.Lock()
+	l.hookQueue = append(l.hookQueue, call)
+	start := !l.hookRunning
+	l.hookRunning = true
+	l.hookMu.Unlock()
+
+	// 单协程执行: 已在运行时由当前协程继续处理新加入的回调
+	if start {
+		l.wg.Go(l.runHooks)
+	}
+}
+
+// runHooks 依次执行队列中的回调, 队列为空时退出
+func (l *LogRotateX) runHooks() {
+	for {
+		l.hookMu.Lock()
+		calls := l.hookQueue
+		l.hookQueue = nil
+		if len(calls) == 0 {
+			l.hookRunning = false
+			l.hookMu.Unlock()
+			return
+		}
+		l.hookMu.Unlock()
+
+		for _, call := range calls {
+			callHook(call)
+		}
+	}
+}
+
+// callHook 执行单个回调, 回调 panic 时写入标准错误输出 (不交给 ErrorHandler, 避免其自身 panic 时循环), 不影响后续回调
+func callHook(call func()) {
+	defer func() {
+		if r := recover(); r != nil {
+			fmt.Fprintf(os.Stderr, "logrotatex: panic in callback: %v\n", r)
+		}
+	}()
+	call()
+}
@@ -0,0 +1,111 @@
+// hooks_test.go 包含了生命周期回调 (OnRotate/OnCompress/OnRemove) 的测试用例。
+// 该文件验证回调的路径和时间参数、回调按顺序执行，
+// 以及耗时的回调不会阻塞 Write。
+
+package logrotatex
+
+import (
+	"os"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// TestHooks_RotateCompressRemove 测试轮转、压缩和删除后依次调用回调
+func TestHooks_RotateCompressRemove(t *testing.T) {
+	originalCurrentTime := currentTime
+	originalFake := fakeCurrentTime
+	defer func() {
+		currentTime = originalCurrentTime
+		fakeCurrentTime = originalFake
+	}()
+	currentTime = fakeTime
+	fakeCurrentTime = time.Date(2026, 10, 16, 9, 0, 0, 0, time.UTC)
+
+	dir := makeTempDir("TestHooks_RotateCompressRemove", t)
+	defer func() { _ = os.RemoveAll(dir) }()
+
+	old := filepath.Join(dir, "foobar_20261015090000.log")
+	isNil(os.WriteFile(old, []byte("old"), 0600), t)
+
+	var mu sync.Mutex
+	var events []string
+	var got []FileEvent
+	record := func(kind string) func(FileEvent) {
+		return func(e FileEvent) {
+			mu.Lock()
+			defer mu.Unlock()
+			events = append(events, kind)
+			got = append(got, e)
+		}
+	}
+
+	l := &LogRotateX{
+		LogFilePath:  logFile(dir),
+		MaxFiles:     1,
+		Compress:     true,
+		CompressType: ".zip",
+		OnRotate:     record("rotate"),
+		OnCompress:   record("compress"),
+		OnRemove:     record("remove"),
+	}
+
+	_, err := l.Write([]byte("data"))
+	isNil(err, t)
+	isNil(l.Rotate(), t)
+	isNil(l.Close(), t) // Close 等待回调执行完毕
+
+	backup := filepath.Join(dir, "foobar_20261016090000.log")
+	equals([]string{"rotate", "remove", "compress"}, events, t)
+
+	equals(logFile(dir), got[0].OldPath, t)
+	equals(backup, got[0].NewPath, t)
+	equals(fakeCurrentTime, got[0].BackupTime, t)
+	equals(fakeCurrentTime, got[0].Time, t)
+
+	equals(old, got[1].OldPath, t)
+	equals("", got[1].NewPath, t)
+	equals(time.Date(2026, 10, 15, 9, 0, 0, 0, time.UTC), got[1].BackupTime, t)
+
+	equals(backup, got[2].OldPath, t)
+	equals(filepath.Join(dir, "foobar_20261016090000.zip"), got[2].NewPath, t)
+	exists(got[2].NewPath, t)
+}
+
+// TestHooks_DoNotBlockWrite 测试耗时的回调不会阻塞 Write
+func TestHooks_DoNotBlockWrite(t *testing.T) {
+	dir := makeTempDir("TestHooks_DoNotBlockWrite", t)
+	defer func() { _ = os.RemoveAll(dir) }()
+
+	release := make(chan struct{})
+	var calls atomic.Int32
+	l := &LogRotateX{
+		LogFilePath: logFile(dir),
+		MaxBytes:    10,
+		OnRotate: func(FileEvent) {
+			<-release
+			calls.Add(1)
+		},
+	}
+
+	// 两次轮转, 第一个回调阻塞时写入仍然可以继续
+	for _, s := range []string{"012345678", "012345678", "tail"} {
+		done := make(chan error, 1)
+		go func() {
+			_, err := l.Write([]byte(s))
+			done <- err
+		}()
+		select {
+		case err := <-done:
+			isNil(err, t)
+		case <-time.After(time.Second):
+			t.Fatal("回调阻塞了 Write")
+		}
+	}
+
+	close(release)
+	isNil(l.Close(), t)
+	equals(int32(2), calls.Load(), t)
+}
@@ -6,6 +6,7 @@ package logrotatex
 
 import (
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
 	"strings"
@@ -19,10 +20,6 @@ const (
 	// 格式: YYYYMMDDHHMMSS (年月日时分秒)
 	backupTimeFormat = "20060102150405"
 
-	// expectedTimestampLen 是时间戳的长度, 用于验证文件名中的时间戳是否有效。
-	// 纯数字时间戳长度: "20060102150405" = 14字符
-	expectedTimestampLen = 14
-
 	// defaultMaxSize 是日志文件的最大默认大小(单位: MB), 在未明确设置时使用此值。
 	defaultMaxSize = 10
 
@@ -34,6 +31,12 @@ const (
 
 	// defaultDirPerm 是日志目录的默认权限模式
 	defaultDirPerm = 0700
+
+	// defaultWatchInterval 是监视模式下默认的检查间隔
+	defaultWatchInterval = time.Second
+
+	// defaultFreeSpaceCheckInterval 是写入路径中默认的剩余空间检查间隔
+	defaultFreeSpaceCheckInterval = 10 * time.Second
 )
 
 // getDefaultLogFilePath 生成默认的日志文件路径
@@ -76,6 +79,16 @@ func (l *LogRotateX) initDefaults() error {
 			return
 		}
 
+		// 解析文件名模式 (如 app-%Y%m%d.log)
+		if hasPattern(l.LogFilePath) {
+			pattern, err := parsePattern(l.LogFilePath)
+			if err != nil {
+				initErr = err
+				return
+			}
+			l.pattern = pattern
+		}
+
 		// 确保目录存在
 		dir := filepath.Dir(l.LogFilePath)
 		if err := os.MkdirAll(dir, defaultDirPerm); err != nil {
@@ -83,20 +96,16 @@ func (l *LogRotateX) initDefaults() error {
 			return
 		}
 
-		// 初始化最大文件大小
-		if l.MaxSize <= 0 {
-			l.MaxSize = defaultMaxSize
-		}
-
-		// 初始化最大保留时间
-		if l.MaxAge < 0 {
-			l.MaxAge = 0
-		}
+		// 初始化可配置字段的默认值
+		l.applyFieldDefaults()
 
-		// 初始化最大备份文件数
-		if l.MaxFiles < 0 {
-			l.MaxFiles = 0
+		// 解析定时轮转计划
+		schedule, err := parseSchedule(l.RotateSchedule)
+		if err != nil {
+			initErr = err
+			return
 		}
+		l.schedule = schedule
 
 		// 初始化内部文件权限
 		if l.filePerm == 0 {
@@ -108,25 +117,78 @@ func (l *LogRotateX) initDefaults() error {
 			l.size = 0
 		}
 
-		// 显式设置原子布尔的初始值为 false
-		l.closed.Store(false)
+		// 显式设置原子布尔的初始值为 false (closed 除外: 初始化前可能已经调用了 Close)
 		l.cleanupRunning.Store(false)
 		l.rerunNeeded.Store(false)
 
-		// 初始化压缩类型, 如果为空, 则设置为默认值 zip
-		if l.CompressType.String() == "" {
-			l.CompressType = comprx.CompressTypeZip
+		// 多进程模式: 创建轮转锁和清理锁
+		if l.MultiProcess {
+			l.rotateLock = newProcessLock(l.LogFilePath + rotateLockSuffix)
+			l.cleanupLock = newProcessLock(l.LogFilePath + cleanupLockSuffix)
+		}
+
+		// 启动后台调度协程 (定时轮转或定时清理), 已关闭时不再启动
+		if (l.BackgroundRotate || l.CleanupInterval > 0) && !l.closed.Load() {
+			l.stopCh = make(chan struct{})
+			l.wg.Go(l.runScheduler)
 		}
 	})
 
 	return initErr
 }
 
+// applyFieldDefaults 将可配置字段的零值和无效值设置为默认值, 由 initDefaults 和 ApplyConfig 共用
+func (l *LogRotateX) applyFieldDefaults() {
+	// 初始化最大文件大小
+	if l.MaxSize <= 0 {
+		l.MaxSize = defaultMaxSize
+	}
+
+	// 初始化最大保留时间
+	if l.MaxAge < 0 {
+		l.MaxAge = 0
+	}
+
+	// 初始化最大备份文件数
+	if l.MaxFiles < 0 {
+		l.MaxFiles = 0
+	}
+
+	// 初始化轮转方式, 如果为空, 则设置为默认值 rename
+	if l.RotateMode == "" {
+		l.RotateMode = RotateModeRename
+	}
+
+	// 初始化磁盘已满处理策略, 如果为空, 则设置为默认值 fail
+	if l.DiskFullPolicy == "" {
+		l.DiskFullPolicy = DiskFullPolicyFail
+	}
+
+	// 初始化监视间隔
+	if l.WatchInterval <= 0 {
+		l.WatchInterval = defaultWatchInterval
+	}
+
+	// 初始化剩余空间检查间隔
+	if l.FreeSpaceCheckInterval <= 0 {
+		l.FreeSpaceCheckInterval = defaultFreeSpaceCheckInterval
+	}
+
+	// 初始化压缩类型, 如果为空, 则设置为默认值 zip
+	if l.CompressType.String() == "" {
+		l.CompressType = comprx.CompressTypeZip
+	}
+}
+
 // logInfo 是一个便捷结构体，用于返回文件名及其嵌入的时间戳。
 // 它包含了日志文件的时间戳信息和文件系统信息，用于日志轮转时的文件管理。
 type logInfo struct {
 	// timestamp 是从文件名中解析出的时间戳
 	timestamp time.Time
+	// seq 是同一时间戳内的序号, 序号越大越新
+	seq int
+	// index 是编号备份模式下的编号, 编号越小越新, 0 表示不是编号备份
+	index int
 	// FileInfo 包含文件的基本信息( 大小、修改时间等)
 	os.FileInfo
 }
@@ -141,9 +203,27 @@ type byFormatTime []logInfo
 //   - i, j: 要比较的元素索引
 //
 // 返回值:
-//   - bool: 如果 i 的时间戳晚于 j 则返回 true
+//   - bool: 如果 i 比 j 更新则返回 true
 func (b byFormatTime) Less(i, j int) bool {
-	return b[i].timestamp.After(b[j].timestamp)
+	return b[i].newerThan(b[j])
+}
+
+// newerThan 检查当前文件是否比另一个文件更新, 时间戳相同时序号大的更新。
+//
+// 参数:
+//   - o: 要比较的日志文件信息
+//
+// 返回值:
+//   - bool: 如果当前文件更新则返回 true
+func (f logInfo) newerThan(o logInfo) bool {
+	// 编号备份按编号排序
+	if f.index > 0 && o.index > 0 {
+		return f.index < o.index
+	}
+	if !f.timestamp.Equal(o.timestamp) {
+		return f.timestamp.After(o.timestamp)
+	}
+	return f.seq > o.seq
 }
 
 // Swap 交换两个日志文件的位置。
@@ -162,6 +242,11 @@ func (b byFormatTime) Len() int {
 // 返回值:
 //   - string: 日志文件的完整路径
 func (l *LogRotateX) filename() string {
+	// 文件名模式: 按当前时间展开
+	if l.pattern != nil {
+		return l.pattern.format(l.now())
+	}
+
 	// 如果已经指定了日志文件名, 则直接返回
 	if l.LogFilePath != "" {
 		return l.LogFilePath
@@ -171,12 +256,26 @@ func (l *LogRotateX) filename() string {
 	return getDefaultLogFilePath()
 }
 
+// useDateDir 检查备份文件是否存放在日期目录中。
+// 编号备份和文件名模式下备份文件始终位于日志目录, DateDirLayout 不生效。
+//
+// 返回值:
+//   - bool: true 表示使用日期目录
+func (l *LogRotateX) useDateDir() bool {
+	return l.DateDirLayout && !l.NumberedBackups && l.pattern == nil
+}
+
 // max 返回日志文件轮转的大小阈值。
-// 如果未设置 MaxSize，则使用默认值。
+// 设置了 MaxBytes 时优先使用 MaxBytes, 如果未设置 MaxSize，则使用默认值。
 //
 // 返回值:
 //   - int64: 日志文件的最大允许大小，单位为字节
 func (l *LogRotateX) max() int64 {
+	// 字节粒度的大小限制优先
+	if l.MaxBytes > 0 {
+		return int64(l.MaxBytes)
+	}
+
 	// 如果未设置最大大小, 则使用默认值
 	if l.MaxSize == 0 {
 		return int64(defaultMaxSize * megabyte)
@@ -194,24 +293,24 @@ func (l *LogRotateX) dir() string {
 }
 
 // prefixAndExt 解析日志文件名，分离前缀和扩展名。
-// 使用与 genTimeName 一致的解析方式，避免多次字符串操作。
+// 使用与 Namer 一致的解析方式，避免多次字符串操作。
 //
 // 返回值:
 //   - prefix: 文件名前缀
 //   - ext: 文件扩展名( 包含点号)
 func (l *LogRotateX) prefixAndExt() (prefix, ext string) {
-	filename := filepath.Base(l.filename()) // 获取日志文件的基本名称
-
-	// 使用与 genTimeName 一致的解析方式
-	lastDot := strings.LastIndex(filename, ".")
+	return splitPrefixExt(filepath.Base(l.filename()))
+}
 
-	if lastDot == -1 {
-		// 没有扩展名
-		return filename, ""
+// namer 返回备份文件的命名规则, 未设置时使用 DefaultNamer。
+//
+// 返回值:
+//   - Namer: 备份文件的命名规则
+func (l *LogRotateX) namer() Namer {
+	if l.Namer != nil {
+		return l.Namer
 	}
-
-	// 返回前缀和扩展名
-	return filename[:lastDot], filename[lastDot:]
+	return DefaultNamer
 }
 
 // close 安全地关闭当前打开的日志文件，防止资源泄漏。
@@ -237,20 +336,38 @@ func (l *LogRotateX) close() error {
 	return nil
 }
 
-// rotate 执行日志文件轮转操作，关闭当前文件并创建新文件。
+// rotate 执行日志文件轮转操作，关闭当前文件并创建新文件，备份文件以当前时间命名。
+//
+// 参数:
+//   - reason: 触发轮转的原因, 用于统计
+//
+// 返回值:
+//   - error: 轮转失败时返回错误，否则返回 nil
+func (l *LogRotateX) rotate(reason RotateReason) error {
+	return l.rotateAt(l.now(), reason)
+}
+
+// rotateAt 执行日志文件轮转操作，备份文件以指定时间命名。
+//
+// 参数:
+//   - t: 备份文件名中使用的时间
+//   - reason: 触发轮转的原因, 用于统计
 //
 // 返回值:
 //   - error: 轮转失败时返回错误，否则返回 nil
-func (l *LogRotateX) rotate() error {
+func (l *LogRotateX) rotateAt(t time.Time, reason RotateReason) error {
 	// 调用 close 方法关闭当前的日志文件。
 	if err := l.close(); err != nil {
 		return err
 	}
 
 	// 调用 openNew 方法打开一个新的日志文件。
-	if err := l.openNew(); err != nil {
+	if err := l.openNew(t); err != nil {
 		return fmt.Errorf("failed to open new file during rotation: %w", err)
 	}
+	l.stats.recordRotation(reason)
+	l.sendEvent(Event{Type: EventRotated, Path: l.filename(), BackupPath: l.lastBackupPath, Reason: reason})
+	l.updateBackupLink()
 
 	// 清理操作：按开关选择同步或异步
 	if l.Async {
@@ -259,7 +376,7 @@ func (l *LogRotateX) rotate() error {
 	} else {
 		// 同步：保持兼容
 		if err := l.cleanupSync(); err != nil {
-			fmt.Printf("cleanup failed during rotation: %v\n", err)
+			l.reportError(OpCleanup, l.dir(), err)
 		}
 	}
 
@@ -268,9 +385,12 @@ func (l *LogRotateX) rotate() error {
 
 // openNew 创建新的日志文件，将现有文件重命名为备份文件。
 //
+// 参数:
+//   - t: 备份文件名中使用的时间 (仅在现有文件需要备份时使用)
+//
 // 返回值:
 //   - error: 创建失败时返回错误，否则返回 nil
-func (l *LogRotateX) openNew() error {
+func (l *LogRotateX) openNew(t time.Time) error {
 	// 确保日志文件所在目录存在，使用更安全的目录权限
 	// 如果目录不存在则创建，如果已存在则不执行任何操作
 	if err := os.MkdirAll(l.dir(), defaultDirPerm); err != nil {
@@ -279,6 +399,7 @@ func (l *LogRotateX) openNew() error {
 
 	// 获取日志文件的完整路径
 	name := l.filename()
+	l.lastBackupPath = ""
 
 	// 获取文件的权限模式
 	mode := l.filePerm
@@ -294,21 +415,46 @@ func (l *LogRotateX) openNew() error {
 		mode = info.Mode()
 
 		// 将现有的日志文件重命名为备份文件
-		newname := genTimeName(name, l.LocalTime, l.DateDirLayout)
+		var newname string
+		if l.NumberedBackups {
+			// 编号备份: 持有清理锁移位已有备份, 新备份始终为 .1
+			unlock, err := l.lockCleanup()
+			if err != nil {
+				return err
+			}
+			defer unlock()
+
+			if err := l.shiftBackups(name); err != nil {
+				return fmt.Errorf("unable to shift numbered backups: %w", err)
+			}
+			newname = numberedName(name, 1)
+		} else {
+			newname = l.backupName(name, t)
+		}
 
 		// 如果启用日期目录，确保目标日期目录存在
-		if l.DateDirLayout {
+		if l.useDateDir() {
 			dateDir := filepath.Dir(newname)
 			if err := os.MkdirAll(dateDir, defaultDirPerm); err != nil {
 				return fmt.Errorf("unable to create date directory: %w", err)
 			}
 		}
 
-		// 重命名文件到新路径
-		if renameErr := os.Rename(name, newname); renameErr != nil {
-			return fmt.Errorf("unable to rename log file: %w", renameErr)
+		if l.RotateMode == RotateModeCopyTruncate {
+			// 复制内容到备份文件后原地截断, 保持当前文件的 inode 不变
+			if copyErr := copyTruncateFile(name, newname, mode); copyErr != nil {
+				return fmt.Errorf("unable to copytruncate log file: %w", copyErr)
+			}
+		} else {
+			// 重命名文件到新路径
+			if renameErr := os.Rename(name, newname); renameErr != nil {
+				return fmt.Errorf("unable to rename log file: %w", renameErr)
+			}
 		}
 
+		l.lastBackupPath = newname
+		l.emit(l.OnRotate, FileEvent{OldPath: name, NewPath: newname, BackupTime: t})
+
 		// // 在非 Linux 系统上, 此操作无效
 		// if chownErr := chown(name, info); chownErr != nil {
 		// 	return fmt.Errorf("unable to set file owner: %w", chownErr)
@@ -317,76 +463,181 @@ func (l *LogRotateX) openNew() error {
 
 	// 使用 truncate 打开文件, 确保文件存在且可写入。
 	// 如果文件已存在( 可能是其他进程创建的), 则清空内容。
-	f, err := os.OpenFile(name, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, mode)
+	// copytruncate 模式下文件已被原地截断, 不再截断, 保留复制之后其他程序追加的内容。
+	// 使用追加模式, 保证多个进程共享同一文件时写入不会互相覆盖。
+	flag := os.O_CREATE | os.O_WRONLY | os.O_APPEND
+	if l.RotateMode != RotateModeCopyTruncate {
+		flag |= os.O_TRUNC
+	}
+	f, err := os.OpenFile(name, flag, mode)
 	if err != nil {
 		return fmt.Errorf("unable to open new log file: %w", err)
 	}
+	var size int64
+	if flag&os.O_TRUNC == 0 {
+		if info, err := f.Stat(); err == nil {
+			size = info.Size()
+		}
+	}
 
 	// 先保存旧文件引用
 	oldFile := l.file
 
 	// 立即设置新文件状态( 确保状态一致性)
 	l.file = f
-	l.size = 0
+	l.size = size
+	l.openedAt = currentTime()
+	l.updatePatternSwitch()
+	l.sendEvent(Event{Type: EventOpened, Path: name, Time: l.openedAt})
+	l.updateLink()
 
 	// 然后尝试关闭旧文件( 失败也不影响新文件的使用)
 	if oldFile != nil {
 		if closeErr := oldFile.Close(); closeErr != nil {
 			// 记录错误但不返回失败，因为新文件已经可用
-			fmt.Printf("warning - failed to close old file: %v\n", closeErr)
+			l.reportError(OpClose, oldFile.Name(), closeErr)
 		}
 	}
 
 	return nil
 }
 
+// copyTruncateFile 执行 copytruncate 轮转, 测试中替换为在截断后模拟其他程序写入的实现
+var copyTruncateFile = copyTruncate
+
+// copyTruncate 将 src 的内容复制到 dst 并同步到磁盘, 然后将 src 原地截断为 0 字节。
+//
+// 参数:
+//   - src: 当前日志文件路径
+//   - dst: 备份文件路径
+//   - mode: 备份文件的权限模式
+//
+// 返回值:
+//   - error: 复制、同步或截断失败时返回错误，否则返回 nil
+func copyTruncate(src, dst string, mode os.FileMode) error {
+	in, err := os.OpenFile(src, os.O_RDWR, 0)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = in.Close() }()
+
+	out, err := os.OpenFile(dst, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, mode)
+	if err != nil {
+		return err
+	}
+
+	// 复制并同步备份文件, 确保截断前数据已经落盘
+	if _, err := io.Copy(out, in); err != nil {
+		_ = out.Close()
+		return err
+	}
+	if err := out.Sync(); err != nil {
+		_ = out.Close()
+		return err
+	}
+	if err := out.Close(); err != nil {
+		return err
+	}
+
+	// 原地截断, 其他持有该文件句柄的程序继续写入同一个文件
+	return in.Truncate(0)
+}
+
+// backupName 生成唯一且按时间单调递增的备份文件名。
+//
+// 备份时间早于上次备份时间 (时钟回拨) 时沿用上次备份时间;
+// 同一时间戳内多次轮转时从已有备份的最大序号之后开始递增, 生成的文件名 (或其压缩文件) 已存在时继续递增,
+// 避免覆盖已有备份, 也避免复用被清理删除的较小序号 (复用的文件名排序最旧, 会被立即清理)。
+//
+// 参数:
+//   - name: 原始文件名
+//   - t: 备份时间
+//
+// 返回值:
+//   - string: 带时间戳的备份文件名
+func (l *LogRotateX) backupName(name string, t time.Time) string {
+	if t.Before(l.lastBackupTime) {
+		t = l.lastBackupTime
+	}
+	l.lastBackupTime = t
+
+	namer := l.namer()
+	base := filepath.Base(name)
+	_, ext := splitPrefixExt(base)
+	compressSuffix := l.CompressType.String()
+
+	// taken 检查备份文件或其压缩文件是否已存在
+	taken := func(newname string) bool {
+		return fileExists(newname) ||
+			(compressSuffix != "" && fileExists(strings.TrimSuffix(newname, ext)+compressSuffix))
+	}
+
+	// 序号从上次使用的序号继续, 只在内存中的状态无法判断时扫描目录
+	stamp := namer.Name(base, t, 0)
+	seq := 0
+	switch {
+	case l.lastBackupStamp == "" || l.MultiProcess:
+		// 首次轮转 (可能是重启后) 或其他进程也会生成备份: 从磁盘上已有的最大序号继续
+		seq = l.nextBackupSeq(base, t, namer)
+	case stamp == l.lastBackupStamp:
+		// 同一时间戳内连续轮转
+		seq = l.lastBackupSeq + 1
+	case taken(genTimeName(name, t, 0, namer, l.useDateDir())):
+		// 新的时间戳已有不是本实例生成的备份
+		seq = l.nextBackupSeq(base, t, namer)
+	}
+
+	for ; ; seq++ {
+		newname := genTimeName(name, t, seq, namer, l.useDateDir())
+		if !taken(newname) {
+			l.lastBackupStamp, l.lastBackupSeq = stamp, seq
+			return newname
+		}
+	}
+}
+
+// nextBackupSeq 扫描目录, 返回时间戳 t 的起始序号: 磁盘上该时间戳带序号的备份中最大序号加一, 没有时为 0。
+// 不带序号的备份 (序号 0) 由 backupName 的存在性检查处理。
+func (l *LogRotateX) nextBackupSeq(base string, t time.Time, namer Namer) int {
+	files, err := l.oldLogFiles()
+	if err != nil {
+		return 0
+	}
+
+	// 按命名规则格式化后比较, 与文件名中时间戳的精度一致
+	stamp := namer.Name(base, t, 0)
+	seq := 0
+	for _, f := range files {
+		if f.index == 0 && f.seq > 0 && f.seq >= seq && namer.Name(base, f.timestamp, 0) == stamp {
+			seq = f.seq + 1
+		}
+	}
+	return seq
+}
+
+// fileExists 检查文件是否存在
+func fileExists(name string) bool {
+	_, err := os.Lstat(name)
+	return err == nil
+}
+
 // genTimeName 根据原始文件名生成带时间戳的备份文件名
 //
 // 参数:
 //   - name: 原始文件名
-//   - local: 是否使用本地时间, false 使用 UTC 时间
+//   - t: 备份时间 (调用方已按 LocalTime 配置转换时区)
+//   - seq: 同一时间戳内的序号, 0 表示不需要序号
+//   - namer: 备份文件的命名规则
 //   - dateDirLayout: 是否启用日期目录布局
 //
 // 返回值:
 //   - string: 带时间戳的备份文件名
-func genTimeName(name string, local bool, dateDirLayout bool) string {
+func genTimeName(name string, t time.Time, seq int, namer Namer, dateDirLayout bool) string {
 	// 获取文件所在的目录
 	dir := filepath.Dir(name)
 
-	// 获取文件的基本名称( 包含扩展名)
-	filename := filepath.Base(name)
-
-	// 一次性解析文件名各部分，避免多次字符串操作
-	lastDot := strings.LastIndex(filename, ".")
-	var prefix, ext string
-
-	switch lastDot {
-	case -1:
-		// 没有扩展名
-		prefix = filename
-		ext = ""
-	case 0:
-		// 以点号开头的文件（如.gitignore）
-		prefix = filename
-		ext = ""
-	default:
-		// 正常情况：有扩展名
-		prefix = filename[:lastDot]
-		ext = filename[lastDot:]
-	}
-
-	// 获取当前时间
-	t := currentTime()
-	// 如果未指定使用本地时间, 则将时间转换为 UTC
-	if !local {
-		t = t.UTC()
-	}
-
-	// 格式化时间戳
-	timestamp := t.Format(backupTimeFormat)
-
-	// 生成带时间戳的文件名部分
-	timedName := fmt.Sprintf("%s_%s%s", prefix, timestamp, ext)
+	// 按命名规则生成带时间戳的文件名部分
+	timedName := namer.Name(filepath.Base(name), t, seq)
 
 	// 如果启用日期目录，生成日期目录名
 	if dateDirLayout {
@@ -416,17 +667,28 @@ func (l *LogRotateX) openExistingOrNew(writeLen int) error {
 	info, err := os.Stat(filename)
 	if os.IsNotExist(err) {
 		// 如果文件不存在, 直接创建新文件
-		return l.openNew()
+		return l.openNew(l.now())
 	}
 	if err != nil {
 		// 如果获取文件信息失败, 返回错误
 		return fmt.Errorf("error getting log file info: %w", err)
 	}
 
+	// 按天轮转: 首次打开已有文件时 (如服务重启) 以文件的修改时间判断其所属日期,
+	// 文件属于更早的日期时立即轮转, 备份文件以文件的实际日期命名
+	if l.RotateByDay && l.lastRotationDate.IsZero() {
+		now := l.now()
+		modTime := info.ModTime().In(now.Location())
+		l.lastRotationDate = now
+		if info.Size() > 0 && modTime.Before(now) && !sameDay(modTime, now) {
+			return l.rotateAt(modTime, RotateReasonTime)
+		}
+	}
+
 	// 检查写入操作是否会达到或超出最大文件大小限制
 	if info.Size()+int64(writeLen) >= l.max() {
 		// 如果会达到或超出限制, 则执行日志文件的轮转操作
-		return l.rotate()
+		return l.rotate(RotateReasonSize)
 	}
 
 	// 以追加模式打开现有日志文件
@@ -436,7 +698,7 @@ func (l *LogRotateX) openExistingOrNew(writeLen int) error {
 	}
 	file, err := os.OpenFile(filename, os.O_APPEND|os.O_WRONLY, filePerm)
 	if err != nil {
-		return l.openNew() // 如果打开文件失败, 则创建新文件
+		return l.openNew(l.now()) // 如果打开文件失败, 则创建新文件
 	}
 
 	// 先保存旧文件引用
@@ -445,28 +707,77 @@ func (l *LogRotateX) openExistingOrNew(writeLen int) error {
 	// 立即更新日志对象的文件句柄和当前文件大小
 	l.file = file
 	l.size = info.Size()
+	l.openedAt = currentTime()
+	l.updatePatternSwitch()
+	l.sendEvent(Event{Type: EventOpened, Path: filename, Time: l.openedAt})
+	l.updateLink()
 
 	// 然后尝试关闭旧文件( 失败也不影响新文件的使用)
 	if oldFile != nil {
 		if closeErr := oldFile.Close(); closeErr != nil {
 			// 记录错误但不返回失败，因为新文件已经可用
-			fmt.Printf("warning - failed to close old file: %v\n", closeErr)
+			l.reportError(OpClose, oldFile.Name(), closeErr)
 		}
 	}
 
 	return nil
 }
 
+// openAppend 以追加模式打开日志文件, 文件不存在时创建, 不执行任何重命名。
+//
+// 返回值:
+//   - error: 打开文件失败时返回错误，否则返回 nil
+func (l *LogRotateX) openAppend() error {
+	// 确保日志文件所在目录存在 (可能已被外部删除)
+	if err := os.MkdirAll(l.dir(), defaultDirPerm); err != nil {
+		return fmt.Errorf("unable to create required directory for log file: %w", err)
+	}
+
+	// 获取文件的权限模式
+	filePerm := l.filePerm
+	if filePerm == 0 {
+		filePerm = os.FileMode(defaultFilePerm)
+	}
+
+	f, err := os.OpenFile(l.filename(), os.O_CREATE|os.O_WRONLY|os.O_APPEND, filePerm)
+	if err != nil {
+		return fmt.Errorf("unable to open log file: %w", err)
+	}
+
+	// 以文件的实际大小作为当前大小
+	info, err := f.Stat()
+	if err != nil {
+		_ = f.Close()
+		return fmt.Errorf("error getting log file info: %w", err)
+	}
+
+	l.file = f
+	l.size = info.Size()
+	l.openedAt = currentTime()
+	l.updatePatternSwitch()
+	l.sendEvent(Event{Type: EventOpened, Path: f.Name(), Time: l.openedAt})
+	l.updateLink()
+	return nil
+}
+
+// timeRotationDue 检查是否跨天 (RotateByDay) 或到达定时轮转边界 (RotateSchedule)。
+// 两个条件都会被检查以更新各自的状态, 同时满足时只返回一次 true。
+//
+// 返回值:
+//   - bool: true 表示需要轮转, false 表示不需要轮转
+func (l *LogRotateX) timeRotationDue() bool {
+	byDay := l.RotateByDay && l.shouldRotateByDay()
+	bySchedule := l.schedule != nil && l.shouldRotateBySchedule()
+	return byDay || bySchedule
+}
+
 // shouldRotateByDay 检查是否需要按天轮转
 //
 // 返回值:
 //   - bool: true 表示需要轮转, false 表示不需要轮转
 func (l *LogRotateX) shouldRotateByDay() bool {
 	// 获取当前时间（考虑 LocalTime 配置）
-	now := currentTime()
-	if !l.LocalTime {
-		now = now.UTC()
-	}
+	now := l.now()
 
 	// 如果是首次运行，记录当前日期但不轮转
 	if l.lastRotationDate.IsZero() {
@@ -475,11 +786,7 @@ func (l *LogRotateX) shouldRotateByDay() bool {
 	}
 
 	// 检查是否跨天（直接比较时间分量）
-	nowYear, nowMonth, nowDay := now.Date()
-	lastYear, lastMonth, lastDay := l.lastRotationDate.Date()
-
-	// 如果年、月、日有任何不匹配，说明跨天了
-	if nowYear != lastYear || nowMonth != lastMonth || nowDay != lastDay {
+	if !sameDay(now, l.lastRotationDate) {
 		// 跨天了，更新上次轮转日期
 		l.lastRotationDate = now
 		return true
@@ -487,3 +794,52 @@ func (l *LogRotateX) shouldRotateByDay() bool {
 
 	return false
 }
+
+// shouldRotateBySchedule 检查是否到达定时轮转边界。
+// 下一个边界只在首次检查和跨过边界时计算, 普通写入只做一次时间比较。
+//
+// 返回值:
+//   - bool: true 表示需要轮转, false 表示不需要轮转
+func (l *LogRotateX) shouldRotateBySchedule() bool {
+	now := l.now()
+
+	// 首次运行, 计算下一个边界但不轮转
+	if l.nextRotation.IsZero() {
+		l.nextRotation = l.schedule.next(now)
+		return false
+	}
+
+	// 未到达边界 (或计划无后续边界)
+	if l.nextRotation.IsZero() || now.Before(l.nextRotation) {
+		return false
+	}
+
+	// 跨过边界, 计算下一个边界
+	l.nextRotation = l.schedule.next(now)
+	return true
+}
+
+// now 返回当前时间, 根据 LocalTime 配置决定使用本地时间或 UTC 时间
+//
+// 返回值:
+//   - time.Time: 当前时间
+func (l *LogRotateX) now() time.Time {
+	t := currentTime()
+	if !l.LocalTime {
+		t = t.UTC()
+	}
+	return t
+}
+
+// sameDay 检查两个时间是否属于同一天 (按各自的时区比较年、月、日)
+//
+// 参数:
+//   - a, b: 需要比较的时间
+//
+// 返回值:
+//   - bool: 年、月、日都相同时返回 true
+func sameDay(a, b time.Time) bool {
+	aYear, aMonth, aDay := a.Date()
+	bYear, bMonth, bDay := b.Date()
+	return aYear == bYear && aMonth == bMonth && aDay == bDay
+}
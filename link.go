@@ -0,0 +1,90 @@
+// link.go 实现了logrotatex包的符号链接维护功能。
+// 通过固定路径的符号链接分别指向当前日志文件和最新的备份文件，
+// 方便 tail -F 等工具、采集程序和运维脚本始终读取最新的日志。
+
+package logrotatex
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// updateLink 将 LinkName 指向当前打开的日志文件, 失败时通过 ErrorHandler 报告, 不影响写入。
+func (l *LogRotateX) updateLink() {
+	if l.LinkName == "" || l.file == nil {
+		return
+	}
+
+	l.linkMu.Lock()
+	defer l.linkMu.Unlock()
+
+	if err := replaceSymlink(l.file.Name(), l.LinkName); err != nil {
+		l.reportError(OpLink, l.LinkName, err)
+	}
+}
+
+// updateBackupLink 将 BackupLinkName 指向最新的备份文件 (压缩后指向压缩文件),
+// 没有备份文件时删除链接。轮转和清理 (压缩、删除) 完成后调用, 失败时通过 ErrorHandler 报告。
+func (l *LogRotateX) updateBackupLink() {
+	if l.BackupLinkName == "" {
+		return
+	}
+
+	// 串行化链接更新, 并在锁内重新扫描, 保证最后一次更新使用最新的文件列表
+	l.linkMu.Lock()
+	defer l.linkMu.Unlock()
+
+	files, err := l.oldLogFiles()
+	if err != nil {
+		l.reportError(OpLink, l.BackupLinkName, err)
+		return
+	}
+
+	if len(files) == 0 {
+		// 只删除符号链接, 不删除同名的普通文件
+		if info, err := os.Lstat(l.BackupLinkName); err == nil && info.Mode()&os.ModeSymlink != 0 {
+			_ = os.Remove(l.BackupLinkName)
+		}
+		return
+	}
+
+	if err := replaceSymlink(l.getFilePath(files[0]), l.BackupLinkName); err != nil {
+		l.reportError(OpLink, l.BackupLinkName, err)
+	}
+}
+
+// replaceSymlink 原子地将符号链接指向新的目标: 先创建临时链接, 再重命名覆盖旧链接,
+// 读取链接的程序不会看到链接不存在的中间状态。
+//
+// 参数:
+//   - target: 链接目标路径
+//   - link: 符号链接路径
+//
+// 返回值:
+//   - error: 创建或重命名链接失败时返回错误
+func replaceSymlink(target, link string) error {
+	// 目标位于链接所在目录 (或其子目录) 时使用相对路径, 目录整体移动后链接仍然有效
+	if rel, err := filepath.Rel(filepath.Dir(link), target); err == nil && !strings.HasPrefix(rel, "..") {
+		target = rel
+	} else if abs, err := filepath.Abs(target); err == nil {
+		target = abs
+	}
+
+	// 已指向目标时无需更新
+	if current, err := os.Readlink(link); err == nil && current == target {
+		return nil
+	}
+
+	tmp := fmt.Sprintf("%s.%d.tmp", link, os.Getpid())
+	_ = os.Remove(tmp)
+	if err := os.Symlink(target, tmp); err != nil {
+		return err
+	}
+	if err := os.Rename(tmp, link); err != nil {
+		_ = os.Remove(tmp)
+		return err
+	}
+	return nil
+}
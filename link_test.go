@@ -0,0 +1,173 @@
+// link_test.go 包含了符号链接 (LinkName/BackupLinkName) 的测试用例。
+// 该文件验证当前文件链接和最新备份链接在轮转、压缩、清理后的指向，
+// 以及扫描备份文件时跳过符号链接。
+//go:build !windows
+// +build !windows
+
+package logrotatex
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// readLink 读取符号链接的目标, 失败时终止测试
+func readLink(t *testing.T, link string) string {
+	t.Helper()
+	target, err := os.Readlink(link)
+	if err != nil {
+		t.Fatalf("读取符号链接 %s 失败: %v", link, err)
+	}
+	return target
+}
+
+// TestLinkName_Current 测试当前文件链接在轮转后仍指向活动日志文件
+func TestLinkName_Current(t *testing.T) {
+	dir := makeTempDir("TestLinkName_Current", t)
+	defer func() { _ = os.RemoveAll(dir) }()
+
+	link := filepath.Join(dir, "foobar.current")
+	l := &LogRotateX{LogFilePath: logFile(dir), LinkName: link}
+	defer func() { _ = l.Close() }()
+
+	_, err := l.Write([]byte("before"))
+	isNil(err, t)
+	equals("foobar.log", readLink(t, link), t)
+
+	isNil(l.Rotate(), t)
+	_, err = l.Write([]byte("after"))
+	isNil(err, t)
+	equals("foobar.log", readLink(t, link), t)
+	existsWithContent(link, []byte("after"), t)
+}
+
+// TestLinkName_Pattern 测试文件名模式下当前文件链接随日志文件切换
+func TestLinkName_Pattern(t *testing.T) {
+	originalCurrentTime := currentTime
+	originalFake := fakeCurrentTime
+	defer func() {
+		currentTime = originalCurrentTime
+		fakeCurrentTime = originalFake
+	}()
+	currentTime = fakeTime
+	fakeCurrentTime = time.Date(2026, 10, 16, 23, 59, 0, 0, time.UTC)
+
+	dir := makeTempDir("TestLinkName_Pattern", t)
+	defer func() { _ = os.RemoveAll(dir) }()
+
+	link := filepath.Join(dir, "app.log")
+	l := &LogRotateX{LogFilePath: filepath.Join(dir, "app-%Y-%m-%d.log"), LinkName: link}
+	defer func() { _ = l.Close() }()
+
+	_, err := l.Write([]byte("day1\n"))
+	isNil(err, t)
+	equals("app-2026-10-16.log", readLink(t, link), t)
+
+	fakeCurrentTime = time.Date(2026, 10, 17, 0, 0, 1, 0, time.UTC)
+	_, err = l.Write([]byte("day2\n"))
+	isNil(err, t)
+	equals("app-2026-10-17.log", readLink(t, link), t)
+	existsWithContent(link, []byte("day2\n"), t)
+	fileCount(dir, 3, t)
+}
+
+// TestBackupLinkName_Rotate 测试最新备份链接在每次轮转后指向新的备份文件, 清理后指向剩余最新的备份
+func TestBackupLinkName_Rotate(t *testing.T) {
+	originalCurrentTime := currentTime
+	originalFake := fakeCurrentTime
+	defer func() {
+		currentTime = originalCurrentTime
+		fakeCurrentTime = originalFake
+	}()
+	currentTime = fakeTime
+	fakeCurrentTime = time.Date(2026, 10, 16, 9, 0, 0, 0, time.UTC)
+
+	dir := makeTempDir("TestBackupLinkName_Rotate", t)
+	defer func() { _ = os.RemoveAll(dir) }()
+
+	link := filepath.Join(dir, "foobar.latest")
+	l := &LogRotateX{LogFilePath: logFile(dir), BackupLinkName: link, MaxFiles: 1}
+	defer func() { _ = l.Close() }()
+
+	_, err := l.Write([]byte("first"))
+	isNil(err, t)
+	notExist(link, t)
+
+	isNil(l.Rotate(), t)
+	equals("foobar_20261016090000.log", readLink(t, link), t)
+
+	fakeCurrentTime = fakeCurrentTime.Add(time.Minute)
+	_, err = l.Write([]byte("second"))
+	isNil(err, t)
+	isNil(l.Rotate(), t)
+	equals("foobar_20261016090100.log", readLink(t, link), t)
+	existsWithContent(link, []byte("second"), t)
+	notExist(filepath.Join(dir, "foobar_20261016090000.log"), t)
+}
+
+// TestBackupLinkName_Compressed 测试压缩完成后最新备份链接指向压缩文件
+func TestBackupLinkName_Compressed(t *testing.T) {
+	originalCurrentTime := currentTime
+	originalFake := fakeCurrentTime
+	defer func() {
+		currentTime = originalCurrentTime
+		fakeCurrentTime = originalFake
+	}()
+	currentTime = fakeTime
+	fakeCurrentTime = time.Date(2026, 10, 16, 9, 0, 0, 0, time.UTC)
+
+	dir := makeTempDir("TestBackupLinkName_Compressed", t)
+	defer func() { _ = os.RemoveAll(dir) }()
+
+	link := filepath.Join(dir, "foobar.latest")
+	l := &LogRotateX{LogFilePath: logFile(dir), BackupLinkName: link, Compress: true, DateDirLayout: true}
+	defer func() { _ = l.Close() }()
+
+	_, err := l.Write([]byte("data"))
+	isNil(err, t)
+	isNil(l.Rotate(), t)
+
+	equals(filepath.Join("2026-10-16", "foobar_20261016090000"+l.CompressType.String()), readLink(t, link), t)
+	exists(link, t)
+}
+
+// TestBackupLinkName_RemovedWithLastBackup 测试清理删除所有备份后链接被删除
+func TestBackupLinkName_RemovedWithLastBackup(t *testing.T) {
+	dir := makeTempDir("TestBackupLinkName_RemovedWithLastBackup", t)
+	defer func() { _ = os.RemoveAll(dir) }()
+
+	old := filepath.Join(dir, "foobar_20000101000000.log")
+	isNil(os.WriteFile(old, []byte("old"), 0600), t)
+	link := filepath.Join(dir, "foobar.latest")
+	isNil(os.Symlink(filepath.Base(old), link), t)
+
+	l := &LogRotateX{LogFilePath: logFile(dir), BackupLinkName: link, MaxAge: 1}
+	defer func() { _ = l.Close() }()
+
+	_, err := l.Write([]byte("data"))
+	isNil(err, t)
+	isNil(l.cleanupSync(), t)
+
+	notExist(old, t)
+	if _, err := os.Lstat(link); !os.IsNotExist(err) {
+		t.Fatalf("期望链接被删除, 实际: %v", err)
+	}
+}
+
+// TestOldLogFiles_SkipsSymlinks 测试扫描备份文件时跳过与备份文件名相同的符号链接
+func TestOldLogFiles_SkipsSymlinks(t *testing.T) {
+	dir := makeTempDir("TestOldLogFiles_SkipsSymlinks", t)
+	defer func() { _ = os.RemoveAll(dir) }()
+
+	backup := filepath.Join(dir, "foobar_20250101000000.log")
+	isNil(os.WriteFile(backup, []byte("data"), 0600), t)
+	isNil(os.Symlink(filepath.Base(backup), filepath.Join(dir, "foobar_20991231000000.log")), t)
+
+	l := &LogRotateX{LogFilePath: logFile(dir)}
+	files, err := l.oldLogFiles()
+	isNil(err, t)
+	equals(1, len(files), t)
+	equals(filepath.Base(backup), files[0].Name(), t)
+}
@@ -8,11 +8,12 @@
 // - 线程安全的设计, 适用于并发环境
 //
 // 注意事项:
-// - 假设只有一个进程在向输出文件写入日志
-// - 多个进程使用相同的配置可能导致异常行为
+// - 默认假设只有一个进程在向输出文件写入日志
+// - 多个进程共享同一日志路径时, 需要启用 MultiProcess 模式
 package logrotatex
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"io"
@@ -36,6 +37,21 @@ var (
 	megabyte = 1024 * 1024
 )
 
+// RotateMode 是日志文件的轮转方式
+type RotateMode string
+
+const (
+	// RotateModeRename 将当前日志文件重命名为备份文件, 再创建新的日志文件 (默认)
+	RotateModeRename RotateMode = "rename"
+
+	// RotateModeCopyTruncate 将当前日志文件的内容复制到备份文件并同步到磁盘, 然后原地截断当前文件。
+	// 适用于其他程序持有日志文件句柄、无法感知重命名的场景。
+	//
+	// 注意: 复制完成到截断之间由其他句柄写入的数据会丢失, 该窗口通常很小但无法完全消除。
+	// 其他句柄需要以追加模式 (O_APPEND) 打开, 否则截断后会在原偏移处继续写入, 形成空洞文件。
+	RotateModeCopyTruncate RotateMode = "copytruncate"
+)
+
 // LogRotateX 是一个 io.WriteCloser, 它会将日志写入指定的文件名。
 //
 // 首次调用 Write 方法时, LogRotateX 会打开或创建日志文件。如果文件已存在且大小小于 MaxSize 兆字节,
@@ -54,6 +70,9 @@ var (
 // 日志文件命名规则:
 //   - 默认格式: name_timestamp.ext, 其中 name 是不带扩展名的文件名, timestamp 是日志轮转时的时间, 格式为 `20060102150405`
 //   - 如果启用 DateDirLayout, 轮转后的日志会存放在 YYYY-MM-DD/ 目录下
+//   - 可以通过 Namer 字段自定义命名规则, 内置 ISO-8601、lumberjack 风格以及包含主机名的规则
+//   - 同一时间戳内多次轮转或时钟回拨时, 备份文件名追加递增序号 (如 name_timestamp_1.ext), 不会覆盖已有备份
+//   - 如果启用 NumberedBackups, 备份文件使用 logrotate 风格的编号 name.ext.1, name.ext.2 ..., 编号越小越新
 //   - 例如, 如果你的 LogRotateX.LogFilePath 是 `/var/log/foo/server.log`,
 //     在 2016 年 11 月 11 日下午 6:30 创建的备份文件名将是 `/var/log/foo/server_20161104183000.log`
 //     如果启用日期目录, 则为 `/var/log/foo/2016-11-11/server_20161111183000.log`
@@ -90,6 +109,11 @@ var (
 type LogRotateX struct {
 	// LogFilePath 是写入日志的文件路径。备份日志文件将保留在同一目录中。
 	// 如果该值为空, 则使用 os.TempDir() 下的 <程序名>_logrotatex.log。
+	//
+	// 文件名部分可以包含 strftime 风格的占位符 (%Y %y %m %d %H %M %S, %% 表示百分号),
+	// 例如 "logs/app-%Y-%m-%d.log"。此时当前日志文件名按当前时间展开 (遵循 LocalTime 配置),
+	// 时间跨过模式的边界后写入新的文件而不是重命名旧文件, 清理时从文件名中按模式还原时间戳。
+	// 按大小轮转仍然有效, 备份文件以展开后的文件名为基础命名; 此时 DateDirLayout 不生效。
 	LogFilePath string `json:"logfilepath" yaml:"logfilepath"`
 
 	// 是否启用异步清理 (单协程、合并触发)
@@ -99,6 +123,27 @@ type LogRotateX struct {
 	// 超过此大小的日志文件将被轮转。
 	MaxSize int `json:"maxsize" yaml:"maxsize"`
 
+	// MaxBytes 是以字节为单位的单个日志文件最大大小, 大于 0 时优先于 MaxSize。
+	// 配置文件中可以写数字 (字节数) 或带单位的字符串, 如 "256KB"、"1.5GiB"。
+	MaxBytes ByteSize `json:"maxbytes" yaml:"maxbytes"`
+
+	// MaxTotalSize 是所有备份文件 (包括压缩文件和日期目录中的文件) 与当前日志文件的总大小上限。
+	// 每次轮转后的清理中, 超出上限时从最旧的备份文件开始删除, 可与 MaxFiles、MaxAge 同时使用。
+	// 默认值为 0, 表示不限制总大小。
+	MaxTotalSize ByteSize `json:"maxtotalsize" yaml:"maxtotalsize"`
+
+	// MinFreeSpace 是日志所在文件系统的剩余空间下限, 低于下限时从最旧的备份文件开始删除。
+	// 默认值为 0, 表示不按剩余空间字节数检查。
+	MinFreeSpace ByteSize `json:"minfreespace" yaml:"minfreespace"`
+
+	// MinFreePercent 是日志所在文件系统的剩余空间占总容量的百分比下限 (0-100),
+	// 与 MinFreeSpace 同时设置时取较大的下限。默认值为 0, 表示不按百分比检查。
+	MinFreePercent float64 `json:"minfreepercent" yaml:"minfreepercent"`
+
+	// FreeSpaceCheckInterval 是 Write 中两次剩余空间检查之间的最小间隔, 轮转后的清理中总会检查。
+	// 默认值为 10 秒。
+	FreeSpaceCheckInterval time.Duration `json:"freespacecheckinterval" yaml:"freespacecheckinterval"`
+
 	// MaxAge 是保留日志文件的天数, 超过此天数的文件将被删除。
 	// 默认值为 0, 表示不按时间删除旧日志文件。
 	MaxAge int `json:"maxage" yaml:"maxage"`
@@ -125,6 +170,46 @@ type LogRotateX struct {
 	// false: 只按文件大小轮转 (默认)
 	RotateByDay bool `json:"rotatebyday" yaml:"rotatebyday"`
 
+	// Namer 是备份文件的命名规则, 同时用于清理时识别备份文件。
+	// 默认为 nil, 表示使用 DefaultNamer (name_20060102150405.ext)。
+	// 内置规则: DefaultNamer, ISONamer, LumberjackNamer, NewHostNamer。
+	Namer Namer `json:"-" yaml:"-"`
+
+	// NumberedBackups 决定是否使用与 logrotate 兼容的编号备份文件名。
+	// true: 备份文件命名为 name.ext.1, name.ext.2 ... (压缩后为 name.ext.1.zip), .1 始终是最新的备份,
+	//       每次轮转时已有备份的编号依次加一; 设置 MaxFiles 时编号最大为 MaxFiles, 此时 Namer 和 DateDirLayout 不生效
+	// false: 使用带时间戳的备份文件名 (默认)
+	NumberedBackups bool `json:"numberedbackups" yaml:"numberedbackups"`
+
+	// LinkName 是指向当前日志文件的符号链接路径 (如 "logs/app.current"), 为空表示不创建。
+	// 每次打开日志文件 (轮转、切换到新的模式文件、重新打开) 后原子地更新链接。
+	LinkName string `json:"linkname" yaml:"linkname"`
+
+	// BackupLinkName 是指向最新备份文件的符号链接路径 (如 "logs/app.latest"), 为空表示不创建。
+	// 轮转后指向新的备份文件, 压缩完成后指向压缩文件; 清理删除备份后指向剩余最新的备份, 没有备份时删除链接。
+	BackupLinkName string `json:"backuplinkname" yaml:"backuplinkname"`
+
+	// RotateSchedule 是定时轮转计划, 到达边界后的首次写入会触发轮转, 为空表示不启用。
+	// 时间按 LocalTime 配置使用本地时间或 UTC 时间计算, 可与按大小、按天轮转同时使用。
+	//
+	// 支持的格式:
+	//   - 固定间隔: "1h", "30m", "@every 6h" (从当天零点开始按间隔对齐, 间隔必须能整除 24h)
+	//   - 预定义描述符: "@hourly", "@daily", "@weekly", "@monthly", "@yearly"
+	//   - 五段式 cron 表达式: "分 时 日 月 周", 例如 "0 4 * * *" 表示每天 04:00, "0 0 1 * *" 表示每月 1 日
+	RotateSchedule string `json:"rotateschedule" yaml:"rotateschedule"`
+
+	// BackgroundRotate 决定是否启用后台定时轮转。
+	// true: 后台协程在下一个轮转边界 (跨天或 RotateSchedule) 唤醒并轮转, 即使期间没有任何写入;
+	//       当前文件为空时不会生成空的备份文件
+	// false: 只在 Write 中检查轮转边界 (默认)
+	BackgroundRotate bool `json:"backgroundrotate" yaml:"backgroundrotate"`
+
+	// CleanupInterval 是后台定时清理的间隔, 到期后按 MaxFiles/MaxAge/Compress 规则清理旧日志。
+	// 默认值为 0, 表示只在轮转后清理。
+	//
+	// 注意: 后台协程在首次调用 Write、Sync 或 Rotate 时启动, 在 Close 时停止。
+	CleanupInterval time.Duration `json:"cleanupinterval" yaml:"cleanupinterval"`
+
 	// CompressType 压缩类型, 默认为: comprx.CompressTypeZip
 	//
 	// 支持的压缩格式：
@@ -138,6 +223,59 @@ type LogRotateX struct {
 	//   - comprx.CompressTypeZlib: zlib 压缩格式
 	CompressType comprx.CompressType `json:"compress_type" yaml:"compress_type"`
 
+	// MultiProcess 决定是否启用多进程安全模式。
+	// true: 轮转与清理期间持有基于锁文件 (<LogFilePath>.lock) 的建议锁,
+	//       并在判断是否轮转前重新读取共享文件的实际大小
+	// false: 假设只有一个进程写入该日志文件 (默认)
+	//
+	// 注意: 启用后每次写入都需要额外的加锁和 Stat 系统调用。
+	MultiProcess bool `json:"multiprocess" yaml:"multiprocess"`
+
+	// WatchFile 决定是否启用日志文件监视模式。
+	// true: 定期检查日志文件是否被外部移动、截断或删除, 发现后自动重新打开 (目录被删除时会重新创建)
+	// false: 始终写入已打开的文件句柄 (默认)
+	WatchFile bool `json:"watchfile" yaml:"watchfile"`
+
+	// WatchInterval 是监视模式下两次检查之间的最小间隔, 检查在 Write 中触发。
+	// 默认值为 1 秒。
+	WatchInterval time.Duration `json:"watchinterval" yaml:"watchinterval"`
+
+	// RotateMode 是日志文件的轮转方式, 默认为 RotateModeRename。
+	//
+	// 支持的轮转方式:
+	//   - RotateModeRename: 重命名当前文件后创建新文件
+	//   - RotateModeCopyTruncate: 复制当前文件到备份文件后原地截断 (存在少量数据丢失窗口)
+	RotateMode RotateMode `json:"rotatemode" yaml:"rotatemode"`
+
+	// DiskFullPolicy 是写入时磁盘已满 (ENOSPC) 或超出磁盘配额 (EDQUOT) 的处理策略, 默认为 DiskFullPolicyFail。
+	//
+	// 支持的策略:
+	//   - DiskFullPolicyFail: 将写入错误返回给调用方
+	//   - DiskFullPolicyEvict: 删除最旧的备份文件后重试一次
+	//   - DiskFullPolicyFallback: 将未写入的数据写入 FallbackWriter
+	//   - DiskFullPolicyDrop: 丢弃未写入的数据并计数 (见 DroppedWrites)
+	//
+	// 除 DiskFullPolicyFail 外, 重复的失败以每分钟最多一次的警告代替。
+	DiskFullPolicy DiskFullPolicy `json:"diskfullpolicy" yaml:"diskfullpolicy"`
+
+	// FallbackWriter 是 DiskFullPolicyFallback 策略下的备用输出, 默认为 os.Stderr。
+	FallbackWriter io.Writer `json:"-" yaml:"-"`
+
+	// OnRotate 在轮转生成备份文件后调用, OldPath 为日志文件, NewPath 为备份文件。
+	// 生命周期回调按事件顺序在单独的协程中执行, 不会阻塞 Write; Close 会等待已触发的回调执行完毕。
+	OnRotate func(FileEvent) `json:"-" yaml:"-"`
+
+	// OnCompress 在备份文件压缩成功后调用, OldPath 为压缩前的备份文件, NewPath 为压缩文件。
+	OnCompress func(FileEvent) `json:"-" yaml:"-"`
+
+	// OnRemove 在清理删除备份文件后调用, OldPath 为被删除的文件。
+	OnRemove func(FileEvent) `json:"-" yaml:"-"`
+
+	// ErrorHandler 接收后台操作 (轮转后的清理、异步清理、后台调度、符号链接更新等) 的错误,
+	// 错误类型为 *OpError。与生命周期回调一样在单独的协程中按顺序调用, 可以安全地写入日志。
+	// 默认为 nil, 表示将错误写入标准错误输出。
+	ErrorHandler func(error) `json:"-" yaml:"-"`
+
 	// 内部状态
 	filePerm         os.FileMode    // filePerm 是日志文件的权限模式。默认值为 0600
 	size             int64          // size 是当前日志文件的大小 (以字节为单位)
@@ -148,7 +286,37 @@ type LogRotateX struct {
 	rerunNeeded      atomic.Bool    // 重跑需求标志: false=不需要重跑, true=需要在本轮后再跑一次
 	wg               sync.WaitGroup // wg 是等待组, 用于等待清理协程退出
 	lastRotationDate time.Time      // lastRotationDate 上次轮转的日期 (只记录日期, 不记录时间)
+	schedule         rotateSchedule // schedule 由 RotateSchedule 解析出的轮转计划, 未启用时为 nil
+	nextRotation     time.Time      // nextRotation 下一个定时轮转边界, 只在跨过边界时重新计算
+	lastBackupTime   time.Time      // lastBackupTime 上次备份文件使用的时间, 保证时钟回拨时备份顺序不乱
+	lastBackupStamp  string         // lastBackupStamp 上次备份文件名中的时间戳部分 (序号为 0 时的文件名), 首次轮转前为空
+	lastBackupSeq    int            // lastBackupSeq 上次备份文件使用的序号
+	stopCh           chan struct{}  // stopCh 后台调度协程的停止信号, 未启用时为 nil
 	once             sync.Once      // 确保初始化只执行一次
+	rotateLock       *processLock   // rotateLock 多进程模式下的轮转锁, 未启用时为 nil
+	cleanupLock      *processLock   // cleanupLock 多进程模式下的清理锁, 未启用时为 nil
+	pattern          *pathPattern   // pattern LogFilePath 的文件名模式, 未使用模式时为 nil
+	patternSwitch    time.Time      // patternSwitch 文件名模式下次可能切换文件的时间, 零值表示下次写入时检查
+	linkMu           sync.Mutex     // linkMu 串行化符号链接的更新, 轮转和异步清理都会更新链接
+	backupMu         sync.Mutex     // backupMu 串行化进程内的清理与编号备份移位, 避免清理期间备份文件被重命名
+	lastWatch        time.Time      // lastWatch 监视模式下上次检查日志文件的时间
+	lastSpaceCheck   time.Time      // lastSpaceCheck 上次在写入路径中检查剩余空间的时间
+	openedAt         time.Time      // openedAt 当前日志文件的打开时间
+	stats            statsCounters  // stats 运行时统计计数器
+	lastDiskFullWarn time.Time      // lastDiskFullWarn 上次报告磁盘已满警告的时间
+	suppressedWarns  int            // suppressedWarns 上次警告后被抑制的磁盘已满失败次数
+	droppedWrites    atomic.Int64   // droppedWrites 因磁盘已满被丢弃的写入次数
+	hookMu           sync.Mutex     // hookMu 保护生命周期回调队列
+	hookQueue        []func()       // hookQueue 等待执行的生命周期回调和错误报告
+	hookRunning      bool           // hookRunning 回调协程是否正在运行
+	lastBackupPath   string         // lastBackupPath 最近一次 openNew 生成的备份文件路径, 没有备份时为空
+	eventsMu         sync.Mutex     // eventsMu 保护事件通道的创建、发送和关闭
+	events           chan Event     // events 生命周期事件通道, 调用 Events 后创建
+	eventsClosed     bool           // eventsClosed 事件通道是否已关闭
+	droppedEvents    atomic.Int64   // droppedEvents 因事件通道已满被丢弃的事件数量
+
+	// configWatchers WatchConfig 监视协程的停止信号, Close 时关闭
+	configWatchers []chan struct{}
 }
 
 // Default 返回一个默认的 LogRotateX 实例, 日志文件路径为 "logs/app.log"。
@@ -217,19 +385,52 @@ func (l *LogRotateX) Write(p []byte) (n int, err error) {
 	l.mu.Lock()
 	defer l.mu.Unlock()
 
+	// 关闭后快速短路, 避免继续 open/rotate/write
+	if l.closed.Load() {
+		return 0, errors.New("write on closed")
+	}
+
 	// 初始化默认值（确保直接通过结构体字面量创建的实例也能正确初始化）
 	if err := l.initDefaults(); err != nil {
 		return 0, err
 	}
 
-	// 关闭后快速短路, 避免继续 open/rotate/write
-	if l.closed.Load() {
-		return 0, errors.New("write on closed")
-	}
+	// 统计写入次数、字节数和写入错误
+	defer func() { l.stats.recordWrite(n, err) }()
 
 	// 计算要写入的数据长度
 	writeLen := int64(len(p))
 
+	// 多进程模式: 持有轮转锁, 并以磁盘上的共享文件状态为准
+	unlock, err := l.lockRotate()
+	if err != nil {
+		return 0, err
+	}
+	defer unlock()
+	if l.MultiProcess {
+		if err = l.syncWithDisk(); err != nil {
+			return 0, err
+		}
+	}
+
+	// 监视模式: 节流检查日志文件是否被外部移动、截断或删除 (多进程模式已逐次检查)
+	if l.WatchFile && !l.MultiProcess {
+		if err = l.watchFile(); err != nil {
+			return 0, err
+		}
+	}
+
+	// 文件名模式: 时间跨过模式的边界后切换到新的日志文件
+	if l.pattern != nil && l.file != nil && !l.now().Before(l.patternSwitch) {
+		if l.file.Name() != l.filename() {
+			if err = l.switchPatternFile(); err != nil {
+				return 0, fmt.Errorf("failed to switch log file: %w", err)
+			}
+		} else {
+			l.updatePatternSwitch()
+		}
+	}
+
 	// 检查文件是否已打开, 如果未打开则尝试打开或创建文件
 	if l.file == nil {
 		if err = l.openExistingOrNew(len(p)); err != nil {
@@ -239,29 +440,38 @@ func (l *LogRotateX) Write(p []byte) (n int, err error) {
 
 	// 检查当前写入是否会导致文件大小达到或超过限制, 如果是则触发轮转
 	if l.size+writeLen >= l.max() {
-		if rotateErr := l.rotate(); rotateErr != nil {
+		if rotateErr := l.rotate(RotateReasonSize); rotateErr != nil {
 			return 0, fmt.Errorf("failed to rotate file: %w", rotateErr)
 		}
 	}
 
-	// 检查是否跨天, 触发按天轮转 (仅在启用时)
-	if l.RotateByDay && l.shouldRotateByDay() {
-		if rotateErr := l.rotate(); rotateErr != nil {
+	// 检查是否跨天或到达定时轮转边界 (仅在启用时)
+	if l.timeRotationDue() {
+		if rotateErr := l.rotate(RotateReasonTime); rotateErr != nil {
 			return 0, fmt.Errorf("failed to rotate file: %w", rotateErr)
 		}
 	}
 
+	// 剩余空间保护: 节流检查, 低于下限时提前删除最旧的备份文件
+	if l.freeSpaceEnabled() {
+		l.checkFreeSpace()
+	}
+
 	// 再次检查文件是否已打开
 	if l.file == nil {
 		return 0, errors.New("file handle is nil after attempting to open or rotate")
 	}
 
 	// 安全地将所有数据写入文件
-	n, err = l.file.Write(p)
+	n, err = writeFile(l.file, p)
+	l.size += int64(n) // 更新当前文件大小
 	if err != nil {
+		// 磁盘已满: 按配置的策略处理
+		if isDiskFull(err) && l.DiskFullPolicy != DiskFullPolicyFail {
+			return l.handleDiskFull(p, n, err)
+		}
 		return n, fmt.Errorf("failed to write to file: %w", err)
 	}
-	l.size += int64(n) // 更新当前文件大小
 
 	return n, nil
 }
@@ -276,14 +486,41 @@ func (l *LogRotateX) Close() error {
 		// 已关闭: 幂等返回
 		return nil
 	}
-	// 执行具体的关闭操作
-	if err := l.close(); err != nil {
+	// 执行具体的关闭操作 (加锁, 避免与后台轮转并发操作文件句柄)
+	l.mu.Lock()
+	// 停止后台调度协程 (在锁内读取, 与 initDefaults 中的创建互斥)
+	stopCh := l.stopCh
+	if stopCh != nil {
+		close(stopCh)
+	}
+	// 停止配置文件监视协程
+	watchers := l.configWatchers
+	l.configWatchers = nil
+	for _, done := range watchers {
+		close(done)
+	}
+	err := l.close()
+	l.mu.Unlock()
+	if err != nil {
+		l.closeEvents()
 		return err
 	}
-	// 若启用异步清理, 等待后台协程收敛
-	if l.Async {
+
+	// 若启用异步清理、后台调度、配置文件监视或生命周期回调, 等待后台协程收敛
+	if l.Async || stopCh != nil || len(watchers) > 0 || l.hooksEnabled() {
 		l.wg.Wait()
 	}
+
+	// 释放多进程模式下的锁文件句柄
+	if l.rotateLock != nil {
+		_ = l.rotateLock.close()
+	}
+	if l.cleanupLock != nil {
+		_ = l.cleanupLock.close()
+	}
+
+	// 后台协程已退出, 不会再有新事件
+	l.closeEvents()
 	return nil
 }
 
@@ -296,16 +533,16 @@ func (l *LogRotateX) Sync() error {
 	l.mu.Lock()
 	defer l.mu.Unlock()
 
+	// 关闭后拒绝同步
+	if l.closed.Load() {
+		return errors.New("sync on closed")
+	}
+
 	// 初始化默认值（确保直接通过结构体字面量创建的实例也能正确初始化）
 	if err := l.initDefaults(); err != nil {
 		return err
 	}
 
-	// 二次检查, 避免与关闭并发竞态
-	if l.closed.Load() {
-		return errors.New("sync on closed")
-	}
-
 	// 检查文件是否已打开, 如果已打开则执行同步操作
 	if l.file != nil {
 		return l.file.Sync()
@@ -313,3 +550,88 @@ func (l *LogRotateX) Sync() error {
 	// 如果文件未打开, 则无需同步, 直接返回 nil
 	return nil
 }
+
+// Rotate 立即执行一次轮转: 将当前日志文件重命名为备份文件并创建新的日志文件,
+// 随后按配置执行同步或异步清理。适用于发布、运维接口等需要手动切分日志的场景。
+//
+// 返回值:
+//   - error: 轮转失败时返回错误, 否则返回 nil
+func (l *LogRotateX) Rotate() error {
+	return l.RotateContext(context.Background())
+}
+
+// RotateContext 与 Rotate 相同, 但在获取锁前后都会检查 ctx 是否已取消。
+//
+// 参数:
+//   - ctx: 上下文, 已取消时直接返回 ctx.Err()
+//
+// 返回值:
+//   - error: 轮转失败或 ctx 已取消时返回错误, 否则返回 nil
+func (l *LogRotateX) RotateContext(ctx context.Context) error {
+	// 加锁前先检查上下文, 避免无意义的等待
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	// 等锁期间上下文可能已被取消, 再次检查
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	// 关闭后拒绝轮转
+	if l.closed.Load() {
+		return errors.New("rotate on closed")
+	}
+
+	// 初始化默认值（确保直接通过结构体字面量创建的实例也能正确初始化）
+	if err := l.initDefaults(); err != nil {
+		return err
+	}
+
+	// 多进程模式: 持有轮转锁, 避免与其他进程同时重命名
+	unlock, err := l.lockRotate()
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	// 执行轮转 (重命名现有文件、创建新文件并触发清理)
+	if err := l.rotate(RotateReasonManual); err != nil {
+		return fmt.Errorf("failed to rotate file: %w", err)
+	}
+	return nil
+}
+
+// Reopen 关闭并重新打开 LogFilePath, 不做任何重命名。
+// 适用于外部工具 (如系统 logrotate) 移走日志文件后通过信号通知程序重新打开的场景。
+//
+// 返回值:
+//   - error: 重新打开失败时返回错误, 否则返回 nil
+func (l *LogRotateX) Reopen() error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	// 关闭后拒绝重新打开
+	if l.closed.Load() {
+		return errors.New("reopen on closed")
+	}
+
+	// 初始化默认值（确保直接通过结构体字面量创建的实例也能正确初始化）
+	if err := l.initDefaults(); err != nil {
+		return err
+	}
+
+	// 关闭当前文件 (可能已被外部移走)
+	if err := l.close(); err != nil {
+		return err
+	}
+
+	// 以追加模式打开日志路径, 文件不存在时创建
+	if err := l.openAppend(); err != nil {
+		return fmt.Errorf("failed to reopen file: %w", err)
+	}
+	return nil
+}
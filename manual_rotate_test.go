@@ -0,0 +1,166 @@
+// manual_rotate_test.go 包含了手动轮转 (Rotate/RotateContext) 与重新打开 (Reopen) 的测试用例。
+// 该文件验证按需轮转时的文件重命名、清理联动、上下文取消与关闭后的行为，
+// 以及外部移走日志文件后重新打开的行为。
+
+package logrotatex
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// TestRotate_Manual 测试手动轮转会生成备份文件并创建新的空日志文件
+func TestRotate_Manual(t *testing.T) {
+	originalCurrentTime := currentTime
+	defer func() { currentTime = originalCurrentTime }()
+	currentTime = fakeTime
+
+	dir := makeTempDir("TestRotate_Manual", t)
+	defer func() { _ = os.RemoveAll(dir) }()
+
+	l := &LogRotateX{LogFilePath: logFile(dir)}
+	defer func() { _ = l.Close() }()
+
+	b := []byte("before rotate")
+	n, err := l.Write(b)
+	isNil(err, t)
+	equals(len(b), n, t)
+
+	// 手动轮转: 旧内容进入备份文件, 当前文件被清空
+	isNil(l.Rotate(), t)
+	existsWithContent(backupFile(dir), b, t)
+	existsWithContent(logFile(dir), []byte{}, t)
+
+	// 轮转后继续写入新文件
+	b2 := []byte("after rotate")
+	_, err = l.Write(b2)
+	isNil(err, t)
+	existsWithContent(logFile(dir), b2, t)
+	fileCount(dir, 2, t)
+}
+
+// TestRotate_BeforeFirstWrite 测试首次写入前调用 Rotate 会轮转磁盘上已存在的文件
+func TestRotate_BeforeFirstWrite(t *testing.T) {
+	originalCurrentTime := currentTime
+	defer func() { currentTime = originalCurrentTime }()
+	currentTime = fakeTime
+
+	dir := makeTempDir("TestRotate_BeforeFirstWrite", t)
+	defer func() { _ = os.RemoveAll(dir) }()
+
+	existing := []byte("existing content")
+	isNil(os.WriteFile(logFile(dir), existing, 0600), t)
+
+	l := &LogRotateX{LogFilePath: logFile(dir)}
+	defer func() { _ = l.Close() }()
+
+	isNil(l.Rotate(), t)
+	existsWithContent(backupFile(dir), existing, t)
+	existsWithContent(logFile(dir), []byte{}, t)
+}
+
+// TestRotate_RunsCleanup 测试手动轮转同样会触发清理规则
+func TestRotate_RunsCleanup(t *testing.T) {
+	originalCurrentTime := currentTime
+	defer func() { currentTime = originalCurrentTime }()
+	currentTime = fakeTime
+
+	dir := makeTempDir("TestRotate_RunsCleanup", t)
+	defer func() { _ = os.RemoveAll(dir) }()
+
+	// 预置一个更旧的备份文件
+	old := filepath.Join(dir, "foobar_20000101000000.log")
+	isNil(os.WriteFile(old, []byte("old"), 0600), t)
+
+	l := &LogRotateX{LogFilePath: logFile(dir), MaxFiles: 1}
+	defer func() { _ = l.Close() }()
+
+	_, err := l.Write([]byte("data"))
+	isNil(err, t)
+	isNil(l.Rotate(), t)
+
+	notExist(old, t)
+	exists(backupFile(dir), t)
+	fileCount(dir, 2, t)
+}
+
+// TestRotateContext_Canceled 测试上下文已取消时不执行轮转
+func TestRotateContext_Canceled(t *testing.T) {
+	dir := makeTempDir("TestRotateContext_Canceled", t)
+	defer func() { _ = os.RemoveAll(dir) }()
+
+	l := &LogRotateX{LogFilePath: logFile(dir)}
+	defer func() { _ = l.Close() }()
+
+	_, err := l.Write([]byte("data"))
+	isNil(err, t)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	err = l.RotateContext(ctx)
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("期望返回 context.Canceled, 实际: %v", err)
+	}
+	fileCount(dir, 1, t)
+}
+
+// TestRotate_OnClosed 测试关闭后调用 Rotate 返回错误
+func TestRotate_OnClosed(t *testing.T) {
+	dir := makeTempDir("TestRotate_OnClosed", t)
+	defer func() { _ = os.RemoveAll(dir) }()
+
+	l := &LogRotateX{LogFilePath: logFile(dir)}
+	_, err := l.Write([]byte("data"))
+	isNil(err, t)
+	isNil(l.Close(), t)
+
+	if err := l.Rotate(); err == nil {
+		t.Fatal("期望关闭后轮转返回错误")
+	}
+	fileCount(dir, 1, t)
+}
+
+// TestReopen_AfterExternalMove 测试外部移走日志文件后 Reopen 会重新创建文件且不重命名
+func TestReopen_AfterExternalMove(t *testing.T) {
+	dir := makeTempDir("TestReopen_AfterExternalMove", t)
+	defer func() { _ = os.RemoveAll(dir) }()
+
+	l := &LogRotateX{LogFilePath: logFile(dir)}
+	defer func() { _ = l.Close() }()
+
+	_, err := l.Write([]byte("before\n"))
+	isNil(err, t)
+
+	moved := filepath.Join(dir, "foobar.log.1")
+	isNil(os.Rename(logFile(dir), moved), t)
+
+	isNil(l.Reopen(), t)
+	_, err = l.Write([]byte("after\n"))
+	isNil(err, t)
+
+	existsWithContent(moved, []byte("before\n"), t)
+	existsWithContent(logFile(dir), []byte("after\n"), t)
+	fileCount(dir, 2, t)
+}
+
+// TestReopen_KeepsContent 测试文件未被移动时 Reopen 继续追加写入原文件
+func TestReopen_KeepsContent(t *testing.T) {
+	dir := makeTempDir("TestReopen_KeepsContent", t)
+	defer func() { _ = os.RemoveAll(dir) }()
+
+	l := &LogRotateX{LogFilePath: logFile(dir)}
+	defer func() { _ = l.Close() }()
+
+	_, err := l.Write([]byte("one\n"))
+	isNil(err, t)
+	isNil(l.Reopen(), t)
+	_, err = l.Write([]byte("two\n"))
+	isNil(err, t)
+
+	existsWithContent(logFile(dir), []byte("one\ntwo\n"), t)
+	fileCount(dir, 1, t)
+}
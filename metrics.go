@@ -0,0 +1,270 @@
+// metrics.go 实现了logrotatex包的 Prometheus 指标导出功能。
+// LogRotateX 和 BufferedWriter 通过 WriteMetrics 以 Prometheus 文本格式 (0.0.4) 输出指标，
+// MetricsHandler 将多个实例的指标合并为一个 http.Handler，不依赖 Prometheus 客户端库。
+// 每个实例的指标带有 path 标签 (日志文件路径)，同名指标只输出一次 HELP 和 TYPE。
+
+package logrotatex
+
+import (
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// metricsContentType 是 Prometheus 文本格式的 Content-Type
+const metricsContentType = "text/plain; version=0.0.4; charset=utf-8"
+
+// 编译时接口实现检查
+var (
+	_ MetricsSource = (*LogRotateX)(nil)
+	_ MetricsSource = (*BufferedWriter)(nil)
+)
+
+// MetricsSource 是可以导出 Prometheus 指标的对象, 由 *LogRotateX 和 *BufferedWriter 实现
+type MetricsSource interface {
+	// WriteMetrics 以 Prometheus 文本格式将指标写入 w
+	WriteMetrics(w io.Writer) error
+
+	// collectMetrics 将指标添加到 m 中, 用于合并多个实例的指标
+	collectMetrics(m *metricSet)
+}
+
+// MetricsHandler 返回以 Prometheus 文本格式输出指标的 http.Handler。
+// 每次请求时读取各实例的指标, 多个实例的同名指标合并在一起, 以 path 标签区分。
+//
+// 参数:
+//   - sources: 要导出指标的实例, 例如 *LogRotateX 和 *BufferedWriter
+//
+// 返回值:
+//   - http.Handler: 指标处理器, 可以直接注册到 /metrics
+func MetricsHandler(sources ...MetricsSource) http.Handler {
+	return metricsHandler(sources)
+}
+
+// metricsHandler 是 MetricsHandler 返回的处理器
+type metricsHandler []MetricsSource
+
+// ServeHTTP 实现 http.Handler 接口
+func (h metricsHandler) ServeHTTP(w http.ResponseWriter, _ *http.Request) {
+	m := newMetricSet()
+	for _, s := range h {
+		if s != nil {
+			s.collectMetrics(m)
+		}
+	}
+
+	w.Header().Set("Content-Type", metricsContentType)
+	_ = m.writeTo(w) // 客户端断开时无需处理
+}
+
+// WriteMetrics 以 Prometheus 文本格式将 LogRotateX 的指标写入 w。
+// 指标包括写入、轮转、压缩、清理的计数和耗时, 当前日志文件的大小, 以及磁盘上备份文件的数量和总大小。
+//
+// 参数:
+//   - w: 输出目标
+//
+// 返回值:
+//   - error: 写入失败时返回错误
+func (l *LogRotateX) WriteMetrics(w io.Writer) error {
+	m := newMetricSet()
+	l.collectMetrics(m)
+	return m.writeTo(w)
+}
+
+// collectMetrics 将 LogRotateX 的指标添加到 m 中
+func (l *LogRotateX) collectMetrics(m *metricSet) {
+	path := l.metricsPath()
+	s := l.Stats()
+
+	m.counter("logrotatex_writes_total", "Number of successful writes.", float64(s.Writes), "path", path)
+	m.counter("logrotatex_written_bytes_total", "Number of bytes written to the log file.", float64(s.BytesWritten), "path", path)
+	m.counter("logrotatex_write_errors_total", "Number of writes that returned an error.", float64(s.WriteErrors), "path", path)
+	m.counter("logrotatex_dropped_writes_total", "Number of writes dropped because the disk was full.", float64(s.DroppedWrites), "path", path)
+
+	for _, r := range []struct {
+		reason RotateReason
+		count  int64
+	}{
+		{RotateReasonSize, s.SizeRotations},
+		{RotateReasonTime, s.TimeRotations},
+		{RotateReasonManual, s.ManualRotations},
+	} {
+		m.counter("logrotatex_rotations_total", "Number of rotations by reason.", float64(r.count), "path", path, "reason", string(r.reason))
+	}
+
+	m.counter("logrotatex_compressions_total", "Number of compressed backup files.", float64(s.Compressions), "path", path)
+	m.counter("logrotatex_compress_input_bytes_total", "Number of bytes read by compression.", float64(s.CompressedIn), "path", path)
+	m.counter("logrotatex_compress_output_bytes_total", "Number of bytes written by compression.", float64(s.CompressedOut), "path", path)
+	m.summary("logrotatex_compress_duration_seconds", "Time spent compressing backup files.", s.CompressDuration.Seconds(), float64(s.Compressions), "path", path)
+
+	m.counter("logrotatex_removals_total", "Number of backup files removed by cleanup.", float64(s.Removals), "path", path)
+	m.counter("logrotatex_cleanup_reruns_total", "Number of async cleanup passes rerun after a new trigger.", float64(s.CleanupReruns), "path", path)
+	m.summary("logrotatex_cleanup_duration_seconds", "Time spent in cleanup passes.", s.CleanupDuration.Seconds(), float64(s.CleanupRuns), "path", path)
+
+	var openedAt float64
+	if !s.OpenedAt.IsZero() {
+		openedAt = float64(s.OpenedAt.UnixNano()) / 1e9
+	}
+	m.gauge("logrotatex_current_file_bytes", "Size of the current log file.", float64(s.CurrentSize), "path", path)
+	m.gauge("logrotatex_current_file_opened_timestamp_seconds", "Time the current log file was opened, 0 if not open.", openedAt, "path", path)
+
+	// 备份文件在抓取时扫描, 持有 l.mu (与首次写入的初始化互斥) 和清理锁 (与 ApplyConfig 修改配置互斥),
+	// 目录不存在时按没有备份处理
+	var backups, backupBytes int64
+	l.mu.Lock()
+	l.backupMu.Lock()
+	files, err := l.oldLogFiles()
+	l.backupMu.Unlock()
+	l.mu.Unlock()
+	if err == nil {
+		for _, f := range files {
+			backups++
+			backupBytes += f.Size()
+		}
+	}
+	m.gauge("logrotatex_backup_files", "Number of backup files on disk.", float64(backups), "path", path)
+	m.gauge("logrotatex_backup_bytes", "Total size of backup files on disk.", float64(backupBytes), "path", path)
+}
+
+// metricsPath 返回指标的 path 标签, 文件名模式下使用模式本身, 避免标签随时间变化。
+// 在锁内读取 LogFilePath (首次 Write 的初始化会清理路径), 按与初始化相同的方式清理, 抓取指标不会初始化实例。
+func (l *LogRotateX) metricsPath() string {
+	l.mu.Lock()
+	path := l.LogFilePath
+	l.mu.Unlock()
+
+	if path == "" {
+		return getDefaultLogFilePath()
+	}
+	return strings.TrimSpace(filepath.Clean(path))
+}
+
+// WriteMetrics 以 Prometheus 文本格式将 BufferedWriter 的指标写入 w。
+// 指标包括缓冲区当前占用 (BufferSize) 和容量, 以及刷新成功和失败的次数。
+// path 标签为底层 *LogRotateX 的日志文件路径或 *os.File 的文件名, 其他写入器为空。
+//
+// 参数:
+//   - w: 输出目标
+//
+// 返回值:
+//   - error: 写入失败时返回错误
+func (bw *BufferedWriter) WriteMetrics(w io.Writer) error {
+	m := newMetricSet()
+	bw.collectMetrics(m)
+	return m.writeTo(w)
+}
+
+// collectMetrics 将 BufferedWriter 的指标添加到 m 中
+func (bw *BufferedWriter) collectMetrics(m *metricSet) {
+	size := bw.BufferSize() // BufferSize 确保初始化完成, 之后 maxBufferSize 不再变化
+	path := writerPath(bw.wc)
+
+	m.gauge("logrotatex_buffer_bytes", "Number of bytes waiting in the buffer.", float64(size), "path", path)
+	m.gauge("logrotatex_buffer_capacity_bytes", "Buffer size that triggers a flush.", float64(bw.maxBufferSize), "path", path)
+	m.counter("logrotatex_buffer_flushes_total", "Number of successful buffer flushes.", float64(bw.flushes.Load()), "path", path)
+	m.counter("logrotatex_buffer_flush_errors_total", "Number of failed buffer flushes.", float64(bw.flushErrors.Load()), "path", path)
+}
+
+// writerPath 返回写入器对应的文件路径, 无法确定时返回空字符串
+func writerPath(w io.Writer) string {
+	switch v := w.(type) {
+	case *LogRotateX:
+		return v.metricsPath()
+	case *os.File:
+		return v.Name()
+	case noCloseWC:
+		return writerPath(v.Writer)
+	}
+	return ""
+}
+
+// metricSet 是按添加顺序排列的指标族集合
+type metricSet struct {
+	families []*metricFamily
+	index    map[string]*metricFamily
+}
+
+// metricFamily 是同名指标的集合, HELP 和 TYPE 只输出一次
+type metricFamily struct {
+	name    string
+	help    string
+	typ     string
+	samples []metricSample
+}
+
+// metricSample 是一个样本值
+type metricSample struct {
+	suffix string   // suffix 是样本名后缀, 如 summary 的 _sum 和 _count
+	labels []string // labels 是标签名和标签值交替排列的列表
+	value  float64
+}
+
+// newMetricSet 创建空的指标集合
+func newMetricSet() *metricSet {
+	return &metricSet{index: make(map[string]*metricFamily)}
+}
+
+// family 返回指定名称的指标族, 不存在时创建
+func (m *metricSet) family(name, typ, help string) *metricFamily {
+	f, ok := m.index[name]
+	if !ok {
+		f = &metricFamily{name: name, help: help, typ: typ}
+		m.index[name] = f
+		m.families = append(m.families, f)
+	}
+	return f
+}
+
+// counter 添加一个 counter 样本
+func (m *metricSet) counter(name, help string, value float64, labels ...string) {
+	f := m.family(name, "counter", help)
+	f.samples = append(f.samples, metricSample{labels: labels, value: value})
+}
+
+// gauge 添加一个 gauge 样本
+func (m *metricSet) gauge(name, help string, value float64, labels ...string) {
+	f := m.family(name, "gauge", help)
+	f.samples = append(f.samples, metricSample{labels: labels, value: value})
+}
+
+// summary 添加一个只有 _sum 和 _count 的 summary 样本
+func (m *metricSet) summary(name, help string, sum, count float64, labels ...string) {
+	f := m.family(name, "summary", help)
+	f.samples = append(f.samples,
+		metricSample{suffix: "_sum", labels: labels, value: sum},
+		metricSample{suffix: "_count", labels: labels, value: count})
+}
+
+// labelEscaper 转义标签值中的反斜杠、双引号和换行符
+var labelEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+// writeTo 以 Prometheus 文本格式将指标写入 w
+func (m *metricSet) writeTo(w io.Writer) error {
+	var b strings.Builder
+	for _, f := range m.families {
+		b.WriteString("# HELP " + f.name + " " + f.help + "\n")
+		b.WriteString("# TYPE " + f.name + " " + f.typ + "\n")
+		for _, s := range f.samples {
+			b.WriteString(f.name + s.suffix)
+			if len(s.labels) > 0 {
+				b.WriteByte('{')
+				for i := 0; i+1 < len(s.labels); i += 2 {
+					if i > 0 {
+						b.WriteByte(',')
+					}
+					b.WriteString(s.labels[i] + `="` + labelEscaper.Replace(s.labels[i+1]) + `"`)
+				}
+				b.WriteByte('}')
+			}
+			b.WriteByte(' ')
+			b.WriteString(strconv.FormatFloat(s.value, 'g', -1, 64)) // 特殊值输出为 +Inf、-Inf 和 NaN
+			b.WriteByte('\n')
+		}
+	}
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
@@ -0,0 +1,141 @@
+// metrics_test.go 包含了 Prometheus 指标导出的测试用例。
+// 该文件通过 httptest 调用 MetricsHandler，并将输出与 testdata/metrics.golden 比较，
+// 使用 go test -run TestMetricsHandler -update 更新 golden 文件。
+
+package logrotatex
+
+import (
+	"bytes"
+	"flag"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// update 为 true 时用实际输出覆盖 golden 文件
+var update = flag.Bool("update", false, "update golden files")
+
+// TestMetricsHandler 测试多个实例的指标合并输出, 并与 golden 文件比较
+func TestMetricsHandler(t *testing.T) {
+	originalCurrentTime := currentTime
+	originalFake := fakeCurrentTime
+	defer func() {
+		currentTime = originalCurrentTime
+		fakeCurrentTime = originalFake
+	}()
+	currentTime = fakeTime
+	fakeCurrentTime = time.Date(2026, 10, 16, 9, 0, 0, 0, time.UTC)
+
+	dir := makeTempDir("TestMetricsHandler", t)
+	defer func() { _ = os.RemoveAll(dir) }()
+
+	l := &LogRotateX{
+		LogFilePath: logFile(dir),
+		MaxBytes:    10,
+		MaxFiles:    1,
+	}
+	bw := NewBufferedWriter(l, &BufCfg{FlushInterval: time.Hour})
+	defer func() { _ = bw.Close() }()
+
+	// 按大小轮转一次, 手动轮转一次, 最旧的备份被清理
+	_, err := l.Write([]byte("012345678"))
+	isNil(err, t)
+	fakeCurrentTime = fakeCurrentTime.Add(time.Minute)
+	_, err = l.Write([]byte("abc"))
+	isNil(err, t)
+	fakeCurrentTime = fakeCurrentTime.Add(time.Minute)
+	isNil(l.Rotate(), t)
+
+	// 刷新一次后留下未刷新的数据
+	_, err = bw.Write([]byte("buffered"))
+	isNil(err, t)
+	isNil(bw.Flush(), t)
+	_, err = bw.Write([]byte("pending"))
+	isNil(err, t)
+
+	// 刷新失败的写入器, path 标签为空
+	failing := NewBufferedWriter(failingWriteCloser{}, &BufCfg{MaxBufferSize: 4, FlushInterval: time.Hour})
+	defer func() { _ = failing.Close() }()
+	_, err = failing.Write([]byte("lost"))
+	if err == nil {
+		t.Fatal("期望刷新失败")
+	}
+
+	rec := httptest.NewRecorder()
+	MetricsHandler(l, bw, failing).ServeHTTP(rec, httptest.NewRequest("GET", "/metrics", nil))
+	equals(metricsContentType, rec.Header().Get("Content-Type"), t)
+
+	// 替换临时目录, 使输出与运行环境无关
+	got := rec.Body.String()
+	escaped := labelEscaper.Replace(dir)
+	got = strings.ReplaceAll(got, escaped, "DIR")
+	got = strings.ReplaceAll(got, dir, "DIR")
+
+	golden := filepath.Join("testdata", "metrics.golden")
+	if *update {
+		isNil(os.MkdirAll("testdata", 0755), t)
+		isNil(os.WriteFile(golden, []byte(got), 0644), t)
+	}
+	want, err := os.ReadFile(golden)
+	isNil(err, t)
+	if got != string(want) {
+		t.Fatalf("指标输出与 %s 不一致:\n%s", golden, got)
+	}
+
+	// WriteMetrics 只输出单个实例的指标
+	var buf bytes.Buffer
+	isNil(bw.WriteMetrics(&buf), t)
+	want = []byte("logrotatex_buffer_bytes{path=\"" + labelEscaper.Replace(logFile(dir)) + "\"} 7\n")
+	if !bytes.Contains(buf.Bytes(), want) || strings.Contains(buf.String(), `path=""`) {
+		t.Fatalf("WriteMetrics 输出不正确:\n%s", buf.String())
+	}
+}
+
+// TestWriteMetrics_FirstWrite 测试首次写入与抓取指标并发时不会竞争初始化 (配合 -race 检查)
+func TestWriteMetrics_FirstWrite(t *testing.T) {
+	dir := makeTempDir("TestWriteMetrics_FirstWrite", t)
+	defer func() { _ = os.RemoveAll(dir) }()
+
+	for i := 0; i < 10; i++ {
+		l := &LogRotateX{LogFilePath: filepath.Join(dir, "./foobar.log")}
+		done := make(chan struct{})
+		go func() {
+			_, _ = l.Write([]byte("data\n"))
+			close(done)
+		}()
+
+		var buf bytes.Buffer
+		isNil(l.WriteMetrics(&buf), t)
+		if !strings.Contains(buf.String(), `path="`+labelEscaper.Replace(logFile(dir))+`"`) {
+			t.Fatalf("指标的 path 标签不是清理后的路径:\n%s", buf.String())
+		}
+		<-done
+		isNil(l.Close(), t)
+	}
+}
+
+// TestWriteMetrics_NoInit 测试抓取指标不会初始化实例: 不创建日志目录, 不启动后台协程
+func TestWriteMetrics_NoInit(t *testing.T) {
+	dir := makeTempDir("TestWriteMetrics_NoInit", t)
+	defer func() { _ = os.RemoveAll(dir) }()
+
+	logDir := filepath.Join(dir, "sub")
+	l := &LogRotateX{LogFilePath: logDir + "/./foobar.log", RotateSchedule: "1h", BackgroundRotate: true}
+	defer func() { _ = l.Close() }()
+
+	var buf bytes.Buffer
+	isNil(l.WriteMetrics(&buf), t)
+	if !strings.Contains(buf.String(), `path="`+labelEscaper.Replace(filepath.Join(logDir, "foobar.log"))+`"`) {
+		t.Fatalf("指标的 path 标签不是清理后的路径:\n%s", buf.String())
+	}
+	if _, err := os.Stat(logDir); !os.IsNotExist(err) {
+		t.Fatalf("抓取指标不应创建日志目录, 实际: %v", err)
+	}
+	l.mu.Lock()
+	stopCh := l.stopCh
+	l.mu.Unlock()
+	equals((chan struct{})(nil), stopCh, t)
+}
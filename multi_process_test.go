@@ -0,0 +1,122 @@
+// multi_process_test.go 包含了多进程安全模式 (MultiProcess) 的测试用例。
+// 该文件通过两个独立的 LogRotateX 实例 (各自持有独立的锁文件句柄) 模拟多个进程
+// 共享同一个日志路径，验证大小同步、轮转后重新打开以及并发写入不丢数据。
+
+package logrotatex
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// TestMultiProcess_SizeResync 测试写入前会以磁盘上的共享文件大小判断是否轮转
+func TestMultiProcess_SizeResync(t *testing.T) {
+	dir := makeTempDir("TestMultiProcess_SizeResync", t)
+	defer func() { _ = os.RemoveAll(dir) }()
+
+	a := &LogRotateX{LogFilePath: logFile(dir), MaxSize: 1, MultiProcess: true}
+	b := &LogRotateX{LogFilePath: logFile(dir), MaxSize: 1, MultiProcess: true}
+	defer func() { _ = a.Close() }()
+	defer func() { _ = b.Close() }()
+
+	half := make([]byte, megabyte/2+1)
+
+	// 两个实例各写入半个限额, 共享文件累计超过 MaxSize
+	_, err := a.Write(half)
+	isNil(err, t)
+	_, err = b.Write(half)
+	isNil(err, t)
+
+	// b 在写入前看到了 a 写入的数据, 因此应触发轮转
+	fileCount(dir, 3, t) // 当前文件 + 备份文件 + 锁文件
+	existsWithContent(logFile(dir), half, t)
+}
+
+// TestMultiProcess_ReopenAfterPeerRotate 测试其他进程轮转后当前进程会重新打开新文件
+func TestMultiProcess_ReopenAfterPeerRotate(t *testing.T) {
+	dir := makeTempDir("TestMultiProcess_ReopenAfterPeerRotate", t)
+	defer func() { _ = os.RemoveAll(dir) }()
+
+	a := &LogRotateX{LogFilePath: logFile(dir), MultiProcess: true}
+	b := &LogRotateX{LogFilePath: logFile(dir), MultiProcess: true}
+	defer func() { _ = a.Close() }()
+	defer func() { _ = b.Close() }()
+
+	_, err := a.Write([]byte("a1\n"))
+	isNil(err, t)
+	_, err = b.Write([]byte("b1\n"))
+	isNil(err, t)
+
+	// a 手动轮转, b 仍持有旧文件的句柄
+	isNil(a.Rotate(), t)
+
+	// b 的下一次写入应进入新文件, 而不是已被重命名的备份文件
+	_, err = b.Write([]byte("b2\n"))
+	isNil(err, t)
+	existsWithContent(logFile(dir), []byte("b2\n"), t)
+}
+
+// TestMultiProcess_ConcurrentWrites 测试多个实例并发写入且频繁轮转时没有数据丢失
+func TestMultiProcess_ConcurrentWrites(t *testing.T) {
+	originalMegabyte := megabyte
+	defer func() { megabyte = originalMegabyte }()
+	megabyte = 1024 // 1KB 即触发轮转
+
+	// 每次取时间都前进一秒, 保证每次轮转生成的备份文件名唯一
+	originalCurrentTime := currentTime
+	defer func() { currentTime = originalCurrentTime }()
+	var tick atomic.Int64
+	base := fakeTime()
+	currentTime = func() time.Time {
+		return base.Add(time.Duration(tick.Add(1)) * time.Second)
+	}
+
+	dir := makeTempDir("TestMultiProcess_ConcurrentWrites", t)
+	defer func() { _ = os.RemoveAll(dir) }()
+
+	const writers = 4
+	const linesPerWriter = 200
+	line := []byte(strings.Repeat("x", 63) + "\n")
+
+	var wg sync.WaitGroup
+	loggers := make([]*LogRotateX, writers)
+	for i := range loggers {
+		loggers[i] = &LogRotateX{LogFilePath: logFile(dir), MaxSize: 1, DateDirLayout: false, MultiProcess: true}
+	}
+	for _, l := range loggers {
+		wg.Add(1)
+		go func(l *LogRotateX) {
+			defer wg.Done()
+			for j := 0; j < linesPerWriter; j++ {
+				if _, err := l.Write(line); err != nil {
+					t.Errorf("写入失败: %v", err)
+					return
+				}
+			}
+		}(l)
+	}
+	wg.Wait()
+	for _, l := range loggers {
+		isNil(l.Close(), t)
+	}
+
+	// 统计所有日志文件 (当前文件与备份文件) 中的总行数
+	entries, err := os.ReadDir(dir)
+	isNil(err, t)
+	total := 0
+	for _, e := range entries {
+		if !strings.HasSuffix(e.Name(), ".log") {
+			continue
+		}
+		data, err := os.ReadFile(filepath.Join(dir, e.Name()))
+		isNil(err, t)
+		total += bytes.Count(data, []byte("\n"))
+	}
+	equals(writers*linesPerWriter, total, t)
+}
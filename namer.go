@@ -0,0 +1,147 @@
+// namer.go 实现了logrotatex包的备份文件命名规则。
+// 该文件定义了 Namer 接口以及内置的命名规则 (默认格式、ISO-8601、lumberjack 风格、主机名/进程号)，
+// 生成备份文件名的轮转流程和扫描旧文件的清理流程使用同一个 Namer，保证清理规则能够识别备份文件。
+
+package logrotatex
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Namer 定义备份文件的命名规则。
+//
+// 同一个 Namer 既用于轮转时生成备份文件名, 也用于清理时从文件名中解析时间戳,
+// Parse 必须能够解析 Name 生成的文件名, 否则清理规则无法识别这些备份文件。
+type Namer interface {
+	// Name 生成备份文件名 (不包含目录)
+	//
+	// 参数:
+	//   - base: 当前日志文件名 (不包含目录), 如 "app.log"
+	//   - t: 备份时间
+	//   - seq: 同一时间戳内的序号, 0 表示不需要序号
+	//
+	// 返回值:
+	//   - string: 备份文件名
+	Name(base string, t time.Time, seq int) string
+
+	// Parse 从备份文件名 (不包含目录和压缩后缀) 中解析备份时间和序号
+	//
+	// 返回值:
+	//   - time.Time: 备份时间
+	//   - int: 同一时间戳内的序号
+	//   - bool: 文件名不符合该命名规则时返回 false
+	Parse(name string) (time.Time, int, bool)
+}
+
+var (
+	// DefaultNamer 是默认的命名规则, 格式为 name_20060102150405.ext
+	DefaultNamer Namer = layoutNamer{sep: "_", layout: backupTimeFormat}
+
+	// ISONamer 使用 ISO-8601 基本格式并精确到毫秒, 格式为 name_20060102T150405.000.ext
+	ISONamer Namer = layoutNamer{sep: "_", layout: "20060102T150405.000"}
+
+	// LumberjackNamer 与 lumberjack 的备份文件名兼容, 格式为 name-2006-01-02T15-04-05.000.ext
+	LumberjackNamer Namer = layoutNamer{sep: "-", layout: "2006-01-02T15-04-05.000"}
+)
+
+// NewHostNamer 创建在备份文件名中包含主机名的命名规则, 适用于多台主机共享同一个 (如 NFS) 目录的场景。
+// 格式为 name_hostname_20060102150405.ext, 包含进程号时为 name_hostname_pid_20060102150405.ext。
+//
+// 注意: 清理时只识别与当前主机名 (和进程号) 一致的备份文件, 各主机只管理自己生成的备份文件。
+// 进程号在重启后会变化, 因此包含进程号时重启前的备份文件不再受清理规则管理。
+//
+// 参数:
+//   - withPID: 是否在文件名中包含进程号
+//
+// 返回值:
+//   - Namer: 命名规则
+func NewHostNamer(withPID bool) Namer {
+	host, err := os.Hostname()
+	if err != nil || host == "" {
+		host = "localhost"
+	}
+	// 替换主机名中可能影响文件名解析的字符
+	token := strings.NewReplacer("/", "-", "\\", "-", "_", "-", ".", "-").Replace(host)
+	if withPID {
+		token = fmt.Sprintf("%s_%d", token, os.Getpid())
+	}
+	return layoutNamer{sep: "_", layout: backupTimeFormat, token: token}
+}
+
+// layoutNamer 是基于定长时间格式的命名规则, 格式为 prefix + sep + [token + sep] + 时间戳 + [_seq] + ext
+type layoutNamer struct {
+	sep    string // 前缀与时间戳之间的分隔符
+	layout string // 时间戳格式, 格式化后的长度必须固定
+	token  string // 前缀与时间戳之间的附加标识 (如主机名), 为空表示不使用
+}
+
+// Name 生成备份文件名
+func (n layoutNamer) Name(base string, t time.Time, seq int) string {
+	prefix, ext := splitPrefixExt(base)
+
+	var b strings.Builder
+	b.Grow(len(base) + len(n.sep)*2 + len(n.token) + len(n.layout) + 8)
+	b.WriteString(prefix)
+	b.WriteString(n.sep)
+	if n.token != "" {
+		b.WriteString(n.token)
+		b.WriteString(n.sep)
+	}
+	b.WriteString(t.Format(n.layout))
+	if seq > 0 {
+		b.WriteByte('_')
+		b.WriteString(strconv.Itoa(seq))
+	}
+	b.WriteString(ext)
+	return b.String()
+}
+
+// Parse 从备份文件名中解析备份时间和序号。
+// 从后向前查找分隔符之后符合时间格式的定长片段, 片段之后只允许出现序号和扩展名。
+func (n layoutNamer) Parse(name string) (time.Time, int, bool) {
+	tsLen := len(n.layout)
+	for i := len(name) - tsLen; i > len(n.sep); i-- {
+		if name[i-len(n.sep):i] != n.sep {
+			continue
+		}
+
+		t, err := time.Parse(n.layout, name[i:i+tsLen])
+		if err != nil {
+			continue
+		}
+
+		// 解析可选的序号, 剩余部分必须为空或为扩展名
+		rest := name[i+tsLen:]
+		seq := 0
+		if after, ok := strings.CutPrefix(rest, "_"); ok {
+			end := strings.IndexByte(after, '.')
+			if end == -1 {
+				end = len(after)
+			}
+			v, err := strconv.Atoi(after[:end])
+			if err != nil || v <= 0 || !isAllDigits(after[:end]) {
+				continue
+			}
+			seq, rest = v, after[end:]
+		}
+		if rest != "" && rest[0] != '.' {
+			continue
+		}
+		return t, seq, true
+	}
+	return time.Time{}, 0, false
+}
+
+// splitPrefixExt 将文件名拆分为前缀和扩展名 (包含点号)。
+// 没有扩展名或以点号开头的文件 (如 .gitignore) 整体作为前缀。
+func splitPrefixExt(filename string) (prefix, ext string) {
+	lastDot := strings.LastIndex(filename, ".")
+	if lastDot <= 0 {
+		return filename, ""
+	}
+	return filename[:lastDot], filename[lastDot:]
+}
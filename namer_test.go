@@ -0,0 +1,150 @@
+// namer_test.go 包含了备份文件命名规则 (Namer) 的测试用例。
+// 该文件验证内置命名规则生成与解析文件名的一致性，
+// 以及清理规则使用同一命名规则识别备份文件 (包括压缩后的备份文件)。
+
+package logrotatex
+
+import (
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"gitee.com/MM-Q/comprx"
+)
+
+// TestNamer_RoundTrip 测试内置命名规则生成的文件名能被解析回相同的时间和序号
+func TestNamer_RoundTrip(t *testing.T) {
+	ts := time.Date(2026, 10, 16, 9, 15, 30, 123*int(time.Millisecond), time.UTC)
+
+	tests := []struct {
+		name  string
+		namer Namer
+		base  string
+		seq   int
+		want  string
+	}{
+		{"default", DefaultNamer, "app.log", 0, "app_20261016091530.log"},
+		{"default_seq", DefaultNamer, "app.log", 3, "app_20261016091530_3.log"},
+		{"default_noext", DefaultNamer, "app", 0, "app_20261016091530"},
+		{"iso", ISONamer, "app.log", 0, "app_20261016T091530.123.log"},
+		{"iso_noext", ISONamer, "app", 2, "app_20261016T091530.123_2"},
+		{"lumberjack", LumberjackNamer, "app.log", 0, "app-2026-10-16T09-15-30.123.log"},
+		{"lumberjack_dashes", LumberjackNamer, "my-app.log", 0, "my-app-2026-10-16T09-15-30.123.log"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := tt.namer.Name(tt.base, ts, tt.seq)
+			equals(tt.want, got, t)
+
+			parsed, seq, ok := tt.namer.Parse(got)
+			if !ok {
+				t.Fatalf("解析 %q 失败", got)
+			}
+			equals(tt.seq, seq, t)
+			equals(got, tt.namer.Name(tt.base, parsed, seq), t)
+		})
+	}
+}
+
+// TestNamer_ParseInvalid 测试不符合命名规则的文件名解析失败
+func TestNamer_ParseInvalid(t *testing.T) {
+	invalid := []string{
+		"app.log",
+		"app_2026101609153.log",
+		"app_2026101609153x.log",
+		"app_20261316091530.log",
+		"app_20261016091530_x.log",
+		"app_20261016091530_0.log",
+		"app_20261016091530suffix",
+		"_20261016091530.log",
+	}
+	for _, name := range invalid {
+		if _, _, ok := DefaultNamer.Parse(name); ok {
+			t.Errorf("期望 %q 解析失败", name)
+		}
+	}
+}
+
+// TestNamer_HostNamer 测试包含主机名和进程号的命名规则
+func TestNamer_HostNamer(t *testing.T) {
+	ts := time.Date(2026, 10, 16, 9, 15, 30, 0, time.UTC)
+
+	for _, withPID := range []bool{false, true} {
+		n := NewHostNamer(withPID)
+		name := n.Name("app.log", ts, 0)
+		if !strings.HasPrefix(name, "app_") || !strings.HasSuffix(name, "_20261016091530.log") {
+			t.Fatalf("文件名格式不正确: %s", name)
+		}
+		if withPID && !strings.Contains(name, "_"+strconv.Itoa(os.Getpid())+"_") {
+			t.Fatalf("文件名中缺少进程号: %s", name)
+		}
+
+		parsed, seq, ok := n.Parse(name)
+		if !ok {
+			t.Fatalf("解析 %q 失败", name)
+		}
+		equals(ts, parsed, t)
+		equals(0, seq, t)
+	}
+}
+
+// TestNamer_RotateAndCleanup 测试自定义命名规则同时用于轮转和清理
+func TestNamer_RotateAndCleanup(t *testing.T) {
+	originalCurrentTime := currentTime
+	originalFake := fakeCurrentTime
+	defer func() {
+		currentTime = originalCurrentTime
+		fakeCurrentTime = originalFake
+	}()
+	currentTime = fakeTime
+	fakeCurrentTime = time.Date(2026, 10, 16, 9, 0, 0, 0, time.UTC)
+
+	dir := makeTempDir("TestNamer_RotateAndCleanup", t)
+	defer func() { _ = os.RemoveAll(dir) }()
+
+	// 其他命名规则的文件不应被清理
+	other := filepath.Join(dir, "foobar_20000101000000.log")
+	isNil(os.WriteFile(other, []byte("other"), 0600), t)
+
+	l := &LogRotateX{LogFilePath: logFile(dir), Namer: LumberjackNamer, MaxFiles: 2}
+	defer func() { _ = l.Close() }()
+
+	for i := range 3 {
+		_, err := l.Write([]byte("data\n"))
+		isNil(err, t)
+		isNil(l.Rotate(), t)
+		fakeCurrentTime = fakeCurrentTime.Add(time.Duration(i+1) * time.Minute)
+	}
+
+	notExist(filepath.Join(dir, "foobar-2026-10-16T09-00-00.000.log"), t)
+	exists(filepath.Join(dir, "foobar-2026-10-16T09-01-00.000.log"), t)
+	exists(filepath.Join(dir, "foobar-2026-10-16T09-03-00.000.log"), t)
+	exists(other, t)
+	fileCount(dir, 4, t)
+}
+
+// TestNamer_CompressedBackupsRetained 测试压缩后的备份文件仍然受清理规则管理
+func TestNamer_CompressedBackupsRetained(t *testing.T) {
+	dir := makeTempDir("TestNamer_CompressedBackupsRetained", t)
+	defer func() { _ = os.RemoveAll(dir) }()
+
+	// 两种压缩文件名形式都应被识别
+	for _, name := range []string{
+		"foobar_20250101000000.zip",
+		"foobar_20250102000000.log.zip",
+		"foobar_20250103000000.log",
+	} {
+		isNil(os.WriteFile(filepath.Join(dir, name), []byte("old"), 0600), t)
+	}
+
+	l := &LogRotateX{LogFilePath: logFile(dir), CompressType: comprx.CompressTypeZip}
+	files, err := l.oldLogFiles()
+	isNil(err, t)
+	equals(3, len(files), t)
+	equals("foobar_20250103000000.log", files[0].Name(), t)
+	equals("foobar_20250101000000.zip", files[2].Name(), t)
+}
@@ -0,0 +1,121 @@
+// numbered.go 实现了logrotatex包的编号备份 (NumberedBackups) 功能。
+// 该文件提供与 logrotate 兼容的 name.ext.N 备份文件命名，
+// 轮转时将已有备份 (包括压缩文件) 的编号依次加一，并从文件名中解析编号供清理使用。
+
+package logrotatex
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// numberedName 生成编号备份文件名
+//
+// 参数:
+//   - name: 日志文件路径
+//   - index: 备份编号, 从 1 开始
+//
+// 返回值:
+//   - string: 编号备份文件路径, 如 app.log.1
+func numberedName(name string, index int) string {
+	return name + "." + strconv.Itoa(index)
+}
+
+// parseBackupIndex 从编号备份文件名中解析编号
+//
+// 参数:
+//   - fileName: 文件名 (不包含目录)
+//   - base: 当前日志文件名 (不包含目录)
+//   - compressSuffix: 压缩文件后缀, 为空表示不检查压缩文件
+//
+// 返回值:
+//   - int: 备份编号
+//   - string: 编号之后的压缩后缀, 未压缩时为空
+//   - bool: 文件名不是编号备份时返回 false
+func parseBackupIndex(fileName, base, compressSuffix string) (int, string, bool) {
+	rest, ok := strings.CutPrefix(fileName, base+".")
+	if !ok {
+		return 0, "", false
+	}
+
+	suffix := ""
+	if compressSuffix != "" {
+		if trimmed, ok := strings.CutSuffix(rest, compressSuffix); ok {
+			rest, suffix = trimmed, compressSuffix
+		}
+	}
+
+	if !isAllDigits(rest) {
+		return 0, "", false
+	}
+	index, err := strconv.Atoi(rest)
+	if err != nil || index <= 0 {
+		return 0, "", false
+	}
+	return index, suffix, true
+}
+
+// shiftBackups 将已有编号备份 (包括压缩文件) 的编号依次加一, 为新的 .1 备份腾出位置。
+// 设置了 MaxFiles 时, 编号已达到 MaxFiles 的备份文件会被删除, 保证编号最大为 MaxFiles。
+// 调用方需持有清理锁。
+//
+// 参数:
+//   - name: 日志文件路径
+//
+// 返回值:
+//   - error: 读取目录、删除或重命名失败时返回错误
+func (l *LogRotateX) shiftBackups(name string) error {
+	dir := filepath.Dir(name)
+	base := filepath.Base(name)
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return fmt.Errorf("unable to read log file directory: %w", err)
+	}
+
+	type backup struct {
+		index  int
+		suffix string
+	}
+	var backups []backup
+	for _, e := range entries {
+		if e.IsDir() {
+			continue
+		}
+		if index, suffix, ok := parseBackupIndex(e.Name(), base, l.CompressType.String()); ok {
+			backups = append(backups, backup{index, suffix})
+		}
+	}
+
+	// 从编号最大的开始移位, 避免覆盖尚未移位的文件
+	sort.Slice(backups, func(i, j int) bool {
+		return backups[i].index > backups[j].index
+	})
+
+	for _, b := range backups {
+		src := numberedName(name, b.index) + b.suffix
+
+		if l.MaxFiles > 0 && b.index >= l.MaxFiles {
+			if err := os.Remove(src); err != nil {
+				if os.IsNotExist(err) {
+					continue
+				}
+				return fmt.Errorf("unable to remove backup %s: %w", src, err)
+			}
+			l.stats.removals.Add(1)
+			l.sendEvent(Event{Type: EventRemoved, Path: src})
+			l.emit(l.OnRemove, FileEvent{OldPath: src})
+			continue
+		}
+
+		dst := numberedName(name, b.index+1) + b.suffix
+		if err := os.Rename(src, dst); err != nil {
+			return fmt.Errorf("unable to rename backup %s: %w", src, err)
+		}
+	}
+	return nil
+}
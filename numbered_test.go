@@ -0,0 +1,130 @@
+// numbered_test.go 包含了编号备份 (NumberedBackups) 的测试用例。
+// 该文件验证轮转时备份编号依次移位、MaxFiles 限制最大编号、
+// 压缩文件随之移位，以及清理时按编号排序。
+
+package logrotatex
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// TestNumberedBackups_Shift 测试每次轮转后 .1 为最新的备份, 已有备份编号依次加一
+func TestNumberedBackups_Shift(t *testing.T) {
+	dir := makeTempDir("TestNumberedBackups_Shift", t)
+	defer func() { _ = os.RemoveAll(dir) }()
+
+	l := &LogRotateX{LogFilePath: logFile(dir), NumberedBackups: true}
+	defer func() { _ = l.Close() }()
+
+	for i := 1; i <= 3; i++ {
+		_, err := fmt.Fprintf(l, "gen%d", i)
+		isNil(err, t)
+		isNil(l.Rotate(), t)
+	}
+
+	existsWithContent(logFile(dir)+".1", []byte("gen3"), t)
+	existsWithContent(logFile(dir)+".2", []byte("gen2"), t)
+	existsWithContent(logFile(dir)+".3", []byte("gen1"), t)
+	existsWithContent(logFile(dir), []byte{}, t)
+	fileCount(dir, 4, t)
+}
+
+// TestNumberedBackups_MaxFiles 测试 MaxFiles 作为最大编号, 超出的备份被删除
+func TestNumberedBackups_MaxFiles(t *testing.T) {
+	dir := makeTempDir("TestNumberedBackups_MaxFiles", t)
+	defer func() { _ = os.RemoveAll(dir) }()
+
+	l := &LogRotateX{LogFilePath: logFile(dir), NumberedBackups: true, MaxFiles: 2}
+	defer func() { _ = l.Close() }()
+
+	for i := 1; i <= 4; i++ {
+		_, err := fmt.Fprintf(l, "gen%d", i)
+		isNil(err, t)
+		isNil(l.Rotate(), t)
+	}
+
+	existsWithContent(logFile(dir)+".1", []byte("gen4"), t)
+	existsWithContent(logFile(dir)+".2", []byte("gen3"), t)
+	notExist(logFile(dir)+".3", t)
+	fileCount(dir, 3, t)
+}
+
+// TestNumberedBackups_CompressedShift 测试压缩后的编号备份随之移位
+func TestNumberedBackups_CompressedShift(t *testing.T) {
+	dir := makeTempDir("TestNumberedBackups_CompressedShift", t)
+	defer func() { _ = os.RemoveAll(dir) }()
+
+	l := &LogRotateX{LogFilePath: logFile(dir), NumberedBackups: true}
+	defer func() { _ = l.Close() }()
+
+	_, err := l.Write([]byte("current"))
+	isNil(err, t)
+
+	// 预置已压缩的 .1 和未压缩的 .2
+	suffix := l.CompressType.String()
+	isNil(os.WriteFile(logFile(dir)+".1"+suffix, []byte("one"), 0600), t)
+	isNil(os.WriteFile(logFile(dir)+".2", []byte("two"), 0600), t)
+
+	isNil(l.Rotate(), t)
+
+	existsWithContent(logFile(dir)+".1", []byte("current"), t)
+	existsWithContent(logFile(dir)+".2"+suffix, []byte("one"), t)
+	existsWithContent(logFile(dir)+".3", []byte("two"), t)
+	notExist(logFile(dir)+".1"+suffix, t)
+}
+
+// TestNumberedBackups_Compress 测试编号备份压缩后保留编号
+func TestNumberedBackups_Compress(t *testing.T) {
+	dir := makeTempDir("TestNumberedBackups_Compress", t)
+	defer func() { _ = os.RemoveAll(dir) }()
+
+	l := &LogRotateX{LogFilePath: logFile(dir), NumberedBackups: true, Compress: true}
+	defer func() { _ = l.Close() }()
+
+	_, err := l.Write([]byte("first"))
+	isNil(err, t)
+	isNil(l.Rotate(), t)
+	_, err = l.Write([]byte("second"))
+	isNil(err, t)
+	isNil(l.Rotate(), t)
+
+	suffix := l.CompressType.String()
+	exists(logFile(dir)+".1"+suffix, t)
+	exists(logFile(dir)+".2"+suffix, t)
+	notExist(logFile(dir)+".1", t)
+	fileCount(dir, 3, t)
+}
+
+// TestNumberedBackups_OldLogFilesOrder 测试清理扫描按编号而不是修改时间排序
+func TestNumberedBackups_OldLogFilesOrder(t *testing.T) {
+	dir := makeTempDir("TestNumberedBackups_OldLogFilesOrder", t)
+	defer func() { _ = os.RemoveAll(dir) }()
+
+	// 修改时间与编号顺序相反
+	base := time.Date(2026, 10, 16, 9, 0, 0, 0, time.UTC)
+	for i, index := range []int{1, 2, 10} {
+		name := logFile(dir) + "." + fmt.Sprint(index)
+		isNil(os.WriteFile(name, []byte("data"), 0600), t)
+		mtime := base.Add(time.Duration(i) * time.Hour)
+		isNil(os.Chtimes(name, mtime, mtime), t)
+	}
+	// 与编号备份无关的文件不会被识别
+	isNil(os.WriteFile(logFile(dir)+".bak", []byte("data"), 0600), t)
+	isNil(os.WriteFile(filepath.Join(dir, "foobar_20250101000000.log"), []byte("data"), 0600), t)
+
+	l := &LogRotateX{LogFilePath: logFile(dir), NumberedBackups: true, MaxFiles: 2}
+	files, err := l.oldLogFiles()
+	isNil(err, t)
+	equals(3, len(files), t)
+	equals("foobar.log.1", files[0].Name(), t)
+	equals("foobar.log.2", files[1].Name(), t)
+	equals("foobar.log.10", files[2].Name(), t)
+
+	remove := l.getFilesToRemove(files)
+	equals(1, len(remove), t)
+	equals("foobar.log.10", remove[0].Name(), t)
+}
@@ -0,0 +1,280 @@
+// pattern.go 实现了logrotatex包的文件名模式 (strftime 风格) 功能。
+// LogFilePath 的文件名部分包含 %Y%m%d 等占位符时，当前日志文件名直接带有日期，
+// 时间跨过模式的边界后切换到新的文件，清理时从文件名中按模式还原时间戳。
+
+package logrotatex
+
+import (
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// patternVerbs 是支持的时间占位符及其固定宽度
+var patternVerbs = map[byte]int{
+	'Y': 4, // 年, 如 2026
+	'y': 2, // 年的后两位, 如 26
+	'm': 2, // 月, 01-12
+	'd': 2, // 日, 01-31
+	'H': 2, // 时, 00-23
+	'M': 2, // 分, 00-59
+	'S': 2, // 秒, 00-59
+}
+
+// patternUnitRank 是时间占位符的粒度, 数值越大越细
+var patternUnitRank = map[byte]int{'Y': 0, 'y': 0, 'm': 1, 'd': 2, 'H': 3, 'M': 4, 'S': 5}
+
+// patternToken 是文件名模式中的一个片段, 字面文本或时间占位符
+type patternToken struct {
+	literal string // literal 是字面文本, verb 为 0 时有效
+	verb    byte   // verb 是时间占位符, 如 'Y'
+}
+
+// pathPattern 是解析后的日志文件路径模式, 占位符只允许出现在文件名部分
+type pathPattern struct {
+	dir    string         // dir 是日志文件所在目录
+	tokens []patternToken // tokens 是文件名部分的片段
+	re     *regexp.Regexp // re 匹配按模式展开后的文件名, 每个占位符对应一个分组
+	unit   byte           // unit 是最细的时间占位符, 决定展开结果多久变化一次
+}
+
+// hasPattern 检查路径中是否包含文件名模式占位符
+func hasPattern(path string) bool {
+	return strings.Contains(path, "%")
+}
+
+// parsePattern 解析日志文件路径模式
+//
+// 支持的占位符: %Y (年), %y (年的后两位), %m (月), %d (日), %H (时), %M (分), %S (秒), %% (百分号)
+//
+// 参数:
+//   - path: 日志文件路径模式, 如 "logs/app-%Y-%m-%d.log"
+//
+// 返回值:
+//   - *pathPattern: 解析后的路径模式
+//   - error: 模式无效时返回错误
+func parsePattern(path string) (*pathPattern, error) {
+	dir, base := filepath.Dir(path), filepath.Base(path)
+	if strings.Contains(dir, "%") {
+		return nil, fmt.Errorf("invalid log file pattern %q: placeholders are only supported in the file name", path)
+	}
+
+	p := &pathPattern{dir: dir}
+	var expr strings.Builder
+	expr.WriteString("^")
+
+	var literal strings.Builder
+	flush := func() {
+		if literal.Len() > 0 {
+			p.tokens = append(p.tokens, patternToken{literal: literal.String()})
+			expr.WriteString(regexp.QuoteMeta(literal.String()))
+			literal.Reset()
+		}
+	}
+
+	hasVerb := false
+	for i := 0; i < len(base); i++ {
+		if base[i] != '%' {
+			literal.WriteByte(base[i])
+			continue
+		}
+		if i+1 >= len(base) {
+			return nil, fmt.Errorf("invalid log file pattern %q: trailing %%", path)
+		}
+		i++
+		if base[i] == '%' {
+			literal.WriteByte('%')
+			continue
+		}
+		width, ok := patternVerbs[base[i]]
+		if !ok {
+			return nil, fmt.Errorf("invalid log file pattern %q: unsupported placeholder %%%c", path, base[i])
+		}
+		flush()
+		p.tokens = append(p.tokens, patternToken{verb: base[i]})
+		fmt.Fprintf(&expr, `(\d{%d})`, width)
+		if !hasVerb || patternUnitRank[base[i]] > patternUnitRank[p.unit] {
+			p.unit = base[i]
+		}
+		hasVerb = true
+	}
+	flush()
+	expr.WriteString("$")
+
+	if !hasVerb {
+		return nil, fmt.Errorf("invalid log file pattern %q: no time placeholder", path)
+	}
+
+	re, err := regexp.Compile(expr.String())
+	if err != nil {
+		return nil, fmt.Errorf("invalid log file pattern %q: %w", path, err)
+	}
+	p.re = re
+	return p, nil
+}
+
+// format 按模式展开日志文件路径
+//
+// 参数:
+//   - t: 展开使用的时间 (调用方已按 LocalTime 配置转换时区)
+//
+// 返回值:
+//   - string: 日志文件路径
+func (p *pathPattern) format(t time.Time) string {
+	return filepath.Join(p.dir, p.formatBase(t))
+}
+
+// formatBase 按模式展开日志文件名 (不包含目录)
+func (p *pathPattern) formatBase(t time.Time) string {
+	var b strings.Builder
+	for _, tok := range p.tokens {
+		switch tok.verb {
+		case 0:
+			b.WriteString(tok.literal)
+		case 'Y':
+			fmt.Fprintf(&b, "%04d", t.Year())
+		case 'y':
+			fmt.Fprintf(&b, "%02d", t.Year()%100)
+		case 'm':
+			fmt.Fprintf(&b, "%02d", int(t.Month()))
+		case 'd':
+			fmt.Fprintf(&b, "%02d", t.Day())
+		case 'H':
+			fmt.Fprintf(&b, "%02d", t.Hour())
+		case 'M':
+			fmt.Fprintf(&b, "%02d", t.Minute())
+		case 'S':
+			fmt.Fprintf(&b, "%02d", t.Second())
+		}
+	}
+	return b.String()
+}
+
+// next 返回 t 之后展开结果可能变化的第一个时间, 即最细的占位符的下一个单位的起点。
+// 写入路径只需与该时间比较, 不必每次展开模式。
+//
+// 参数:
+//   - t: 当前时间 (调用方已按 LocalTime 配置转换时区)
+//
+// 返回值:
+//   - time.Time: 下一个边界, 在 t 的时区中计算
+func (p *pathPattern) next(t time.Time) time.Time {
+	year, month, day := t.Date()
+	_, minute, sec := t.Clock()
+	loc := t.Location()
+
+	// 时、分、秒按绝对时间推进: 夏令时跳过的时段不存在对应的时间, 回拨后的边界展开结果可能不变, 由调用方比较文件名
+	elapsed := time.Duration(t.Nanosecond())
+	var n time.Time
+	switch p.unit {
+	case 'S':
+		n = t.Add(time.Second - elapsed)
+	case 'M':
+		n = t.Add(time.Minute - elapsed - time.Duration(sec)*time.Second)
+	case 'H':
+		n = t.Add(time.Hour - elapsed - time.Duration(sec)*time.Second - time.Duration(minute)*time.Minute)
+	case 'd':
+		n = time.Date(year, month, day+1, 0, 0, 0, 0, loc)
+	case 'm':
+		n = time.Date(year, month+1, 1, 0, 0, 0, 0, loc)
+	default:
+		n = time.Date(year+1, time.January, 1, 0, 0, 0, 0, loc)
+	}
+
+	// 防御: 零点处于夏令时切换时 time.Date 的结果可能不晚于 t, 此时在下一秒重新检查
+	if !n.After(t) {
+		n = t.Truncate(time.Second).Add(time.Second)
+	}
+	return n
+}
+
+// parse 从按模式展开的文件名中还原时间, 未出现的字段取最小值。
+// 与 Namer 一致, 返回的时间使用 UTC 时区表示文件名中的数字。
+//
+// 参数:
+//   - name: 文件名 (不包含目录)
+//
+// 返回值:
+//   - time.Time: 文件名中的时间
+//   - bool: 文件名不匹配模式时返回 false
+func (p *pathPattern) parse(name string) (time.Time, bool) {
+	m := p.re.FindStringSubmatch(name)
+	if m == nil {
+		return time.Time{}, false
+	}
+
+	year, month, day, hour, minute, sec := 1, 1, 1, 0, 0, 0
+	group := 1
+	for _, tok := range p.tokens {
+		if tok.verb == 0 {
+			continue
+		}
+		v, _ := strconv.Atoi(m[group])
+		group++
+		switch tok.verb {
+		case 'Y':
+			year = v
+		case 'y':
+			year = 2000 + v
+		case 'm':
+			month = v
+		case 'd':
+			day = v
+		case 'H':
+			hour = v
+		case 'M':
+			minute = v
+		case 'S':
+			sec = v
+		}
+	}
+
+	t := time.Date(year, time.Month(month), day, hour, minute, sec, 0, time.UTC)
+	// 排除超出范围被 time.Date 归一化的值 (如 13 月)
+	if p.formatBase(t) != name {
+		return time.Time{}, false
+	}
+	return t, true
+}
+
+// updatePatternSwitch 在打开日志文件后记录文件名模式下次可能切换文件的时间,
+// 写入路径与该时间比较, 不必每次展开模式。
+func (l *LogRotateX) updatePatternSwitch() {
+	if l.pattern == nil || l.file == nil {
+		return
+	}
+	now := l.now()
+	if l.file.Name() != l.pattern.format(now) {
+		// 打开文件期间已跨过边界, 下次写入时切换
+		l.patternSwitch = now
+		return
+	}
+	l.patternSwitch = l.pattern.next(now)
+}
+
+// switchPatternFile 在时间跨过文件名模式的边界后关闭当前文件, 下次写入时打开新的日志文件,
+// 并按轮转后的规则清理旧文件。
+//
+// 返回值:
+//   - error: 关闭文件失败时返回错误
+func (l *LogRotateX) switchPatternFile() error {
+	var name string
+	if l.file != nil {
+		name = l.file.Name()
+	}
+	if err := l.close(); err != nil {
+		return err
+	}
+	l.stats.recordRotation(RotateReasonTime)
+	l.sendEvent(Event{Type: EventRotated, Path: name, Reason: RotateReasonTime})
+
+	if l.Async {
+		l.cleanupAsync()
+	} else if err := l.cleanupSync(); err != nil {
+		l.reportError(OpCleanup, l.dir(), err)
+	}
+	return nil
+}
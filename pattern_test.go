@@ -0,0 +1,182 @@
+// pattern_test.go 包含了文件名模式 (strftime 风格的 LogFilePath) 的测试用例。
+// 该文件验证模式的解析、展开与还原，时间跨过边界后切换日志文件，
+// 以及清理规则按模式识别旧文件。
+
+package logrotatex
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+	_ "time/tzdata"
+)
+
+// TestPathPattern_FormatParse 测试模式展开后的文件名能还原出相同的时间
+func TestPathPattern_FormatParse(t *testing.T) {
+	ts := time.Date(2026, 10, 16, 9, 5, 7, 0, time.UTC)
+
+	tests := []struct {
+		pattern string
+		want    string
+	}{
+		{"logs/app-%Y-%m-%d.log", "app-2026-10-16.log"},
+		{"logs/app.%Y%m%d%H.log", "app.2026101609.log"},
+		{"logs/%y%m%d-%H%M%S.log", "261016-090507.log"},
+		{"logs/app-100%%-%Y.log", "app-100%-2026.log"},
+		{"logs/app-%Y%m%d", "app-20261016"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.pattern, func(t *testing.T) {
+			p, err := parsePattern(tt.pattern)
+			isNil(err, t)
+			equals(filepath.Join("logs", tt.want), p.format(ts), t)
+
+			parsed, ok := p.parse(tt.want)
+			if !ok {
+				t.Fatalf("解析 %q 失败", tt.want)
+			}
+			equals(tt.want, p.formatBase(parsed), t)
+		})
+	}
+
+	// 不匹配模式或数值超出范围的文件名
+	p, err := parsePattern("logs/app-%Y-%m-%d.log")
+	isNil(err, t)
+	for _, name := range []string{"app.log", "app-2026-13-01.log", "app-2026-10-16.log.bak", "app-26-10-16.log"} {
+		if _, ok := p.parse(name); ok {
+			t.Errorf("期望 %q 不匹配模式", name)
+		}
+	}
+}
+
+// TestPathPattern_Invalid 测试无效的模式返回错误
+func TestPathPattern_Invalid(t *testing.T) {
+	invalid := []string{
+		"logs/%Y/app.log",    // 目录中包含占位符
+		"logs/app-%Q.log",    // 不支持的占位符
+		"logs/app-100%%.log", // 没有时间占位符
+		"logs/app-%",         // 末尾的百分号
+	}
+	for _, pattern := range invalid {
+		if _, err := parsePattern(pattern); err == nil {
+			t.Errorf("期望 %q 解析失败", pattern)
+		}
+	}
+}
+
+// TestPathPattern_Next 测试按最细的占位符计算下一个边界, 包括夏令时切换的日期
+func TestPathPattern_Next(t *testing.T) {
+	ny, err := time.LoadLocation("America/New_York")
+	isNil(err, t)
+	ts := time.Date(2026, 12, 31, 23, 59, 59, 500, time.UTC)
+
+	tests := []struct {
+		pattern string
+		from    time.Time
+		want    time.Time
+	}{
+		{"logs/app-%Y.log", ts, time.Date(2027, 1, 1, 0, 0, 0, 0, time.UTC)},
+		{"logs/app-%Y%m.log", ts, time.Date(2027, 1, 1, 0, 0, 0, 0, time.UTC)},
+		{"logs/app-%d-%Y.log", time.Date(2026, 10, 16, 9, 5, 7, 0, time.UTC), time.Date(2026, 10, 17, 0, 0, 0, 0, time.UTC)},
+		{"logs/app-%Y%m%d%H.log", time.Date(2026, 10, 16, 9, 5, 7, 0, time.UTC), time.Date(2026, 10, 16, 10, 0, 0, 0, time.UTC)},
+		{"logs/app-%M%H.log", time.Date(2026, 10, 16, 9, 5, 7, 0, time.UTC), time.Date(2026, 10, 16, 9, 6, 0, 0, time.UTC)},
+		{"logs/app-%S.log", ts, time.Date(2027, 1, 1, 0, 0, 0, 0, time.UTC)},
+		// 春季跳过 02:00-03:00, 下一个小时从 03:00 开始
+		{"logs/app-%H.log", time.Date(2026, 3, 8, 1, 30, 0, 0, ny), time.Date(2026, 3, 8, 3, 0, 0, 0, ny)},
+		// 秋季回拨后的重复时段
+		{"logs/app-%d.log", time.Date(2026, 11, 1, 1, 30, 0, 0, ny).Add(time.Hour), time.Date(2026, 11, 2, 0, 0, 0, 0, ny)},
+	}
+	for _, tt := range tests {
+		p, err := parsePattern(tt.pattern)
+		isNil(err, t)
+		if got := p.next(tt.from); !got.Equal(tt.want) {
+			t.Errorf("%s: next(%v) = %v, 期望 %v", tt.pattern, tt.from, got, tt.want)
+		}
+	}
+}
+
+// TestPattern_SwitchFile 测试跨过模式边界后写入新的文件
+func TestPattern_SwitchFile(t *testing.T) {
+	originalCurrentTime := currentTime
+	originalFake := fakeCurrentTime
+	defer func() {
+		currentTime = originalCurrentTime
+		fakeCurrentTime = originalFake
+	}()
+	currentTime = fakeTime
+	fakeCurrentTime = time.Date(2026, 10, 16, 23, 59, 0, 0, time.UTC)
+
+	dir := makeTempDir("TestPattern_SwitchFile", t)
+	defer func() { _ = os.RemoveAll(dir) }()
+
+	l := &LogRotateX{LogFilePath: filepath.Join(dir, "app-%Y-%m-%d.log")}
+	defer func() { _ = l.Close() }()
+
+	_, err := l.Write([]byte("day1\n"))
+	isNil(err, t)
+	day1 := filepath.Join(dir, "app-2026-10-16.log")
+	existsWithContent(day1, []byte("day1\n"), t)
+	// 打开文件时记录下一个边界, 边界之前的写入只比较时间
+	equals(time.Date(2026, 10, 17, 0, 0, 0, 0, time.UTC), l.patternSwitch, t)
+
+	// 跨天后写入新的文件, 旧文件保持原名
+	fakeCurrentTime = time.Date(2026, 10, 17, 0, 0, 1, 0, time.UTC)
+	_, err = l.Write([]byte("day2\n"))
+	isNil(err, t)
+	existsWithContent(day1, []byte("day1\n"), t)
+	existsWithContent(filepath.Join(dir, "app-2026-10-17.log"), []byte("day2\n"), t)
+	fileCount(dir, 2, t)
+}
+
+// TestPattern_Retention 测试清理规则识别模式文件、压缩文件以及按大小轮转的备份文件
+func TestPattern_Retention(t *testing.T) {
+	originalCurrentTime := currentTime
+	originalFake := fakeCurrentTime
+	originalMegabyte := megabyte
+	defer func() {
+		currentTime = originalCurrentTime
+		fakeCurrentTime = originalFake
+		megabyte = originalMegabyte
+	}()
+	currentTime = fakeTime
+	fakeCurrentTime = time.Date(2026, 10, 16, 9, 0, 0, 0, time.UTC)
+	megabyte = 1
+
+	dir := makeTempDir("TestPattern_Retention", t)
+	defer func() { _ = os.RemoveAll(dir) }()
+
+	for _, name := range []string{
+		"app-2026-10-13.zip",
+		"app-2026-10-14.log",
+		"app-2026-10-15.log",
+		"other-2026-10-12.log",
+	} {
+		isNil(os.WriteFile(filepath.Join(dir, name), []byte("old"), 0600), t)
+	}
+
+	l := &LogRotateX{
+		LogFilePath: filepath.Join(dir, "app-%Y-%m-%d.log"),
+		MaxSize:     10,
+		MaxFiles:    2,
+	}
+	defer func() { _ = l.Close() }()
+
+	// 超过大小限制触发按大小轮转
+	_, err := l.Write([]byte("012345678"))
+	isNil(err, t)
+	_, err = l.Write([]byte("next"))
+	isNil(err, t)
+
+	files, err := l.oldLogFiles()
+	isNil(err, t)
+	equals(2, len(files), t)
+	equals("app-2026-10-16_20261016090000.log", files[0].Name(), t)
+	equals("app-2026-10-15.log", files[1].Name(), t)
+
+	notExist(filepath.Join(dir, "app-2026-10-13.zip"), t)
+	notExist(filepath.Join(dir, "app-2026-10-14.log"), t)
+	exists(filepath.Join(dir, "other-2026-10-12.log"), t)
+	existsWithContent(filepath.Join(dir, "app-2026-10-16.log"), []byte("next"), t)
+}
@@ -0,0 +1,160 @@
+// process_lock.go 实现了logrotatex包的跨进程互斥锁。
+// 多个进程共享同一个日志路径时，通过对锁文件加 flock 风格的建议锁，
+// 确保同一时刻只有一个进程执行轮转或清理操作。
+
+package logrotatex
+
+import (
+	"fmt"
+	"os"
+	"sync"
+)
+
+const (
+	// rotateLockSuffix 是轮转锁文件的后缀, 锁文件与日志文件位于同一目录
+	rotateLockSuffix = ".lock"
+
+	// cleanupLockSuffix 是清理锁文件的后缀, 与轮转锁分离, 避免压缩期间阻塞其他进程写入
+	cleanupLockSuffix = ".cleanup.lock"
+)
+
+// processLock 是基于锁文件的跨进程互斥锁。
+// 内部的 sync.Mutex 保证同一进程内的多个协程互斥使用同一个文件描述符,
+// 避免某个协程解锁时误释放其他协程持有的建议锁。
+type processLock struct {
+	mu   sync.Mutex // mu 保护 file 并串行化进程内的加锁请求
+	path string     // path 是锁文件路径
+	file *os.File   // file 是锁文件句柄, 首次加锁时打开
+}
+
+// newProcessLock 创建一个新的跨进程锁, 锁文件在首次加锁时创建。
+//
+// 参数:
+//   - path: 锁文件路径
+//
+// 返回值:
+//   - *processLock: 跨进程锁实例
+func newProcessLock(path string) *processLock {
+	return &processLock{path: path}
+}
+
+// lock 获取跨进程锁, 会阻塞直到其他进程释放锁。
+//
+// 返回值:
+//   - error: 打开锁文件或加锁失败时返回错误
+func (p *processLock) lock() error {
+	p.mu.Lock()
+
+	// 懒加载锁文件, 目录在 initDefaults 中已确保存在
+	if p.file == nil {
+		f, err := os.OpenFile(p.path, os.O_CREATE|os.O_RDWR, defaultFilePerm)
+		if err != nil {
+			p.mu.Unlock()
+			return fmt.Errorf("unable to open lock file: %w", err)
+		}
+		p.file = f
+	}
+
+	if err := lockFile(p.file); err != nil {
+		p.mu.Unlock()
+		return fmt.Errorf("unable to lock %s: %w", p.path, err)
+	}
+	return nil
+}
+
+// unlock 释放跨进程锁。
+func (p *processLock) unlock() {
+	if p.file != nil {
+		_ = unlockFile(p.file)
+	}
+	p.mu.Unlock()
+}
+
+// close 关闭锁文件句柄, 锁文件本身保留在磁盘上供其他进程继续使用。
+//
+// 返回值:
+//   - error: 关闭失败时返回错误
+func (p *processLock) close() error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.file == nil {
+		return nil
+	}
+	err := p.file.Close()
+	p.file = nil
+	return err
+}
+
+// lockRotate 在启用多进程模式时获取轮转锁, 未启用时为空操作。
+//
+// 返回值:
+//   - func(): 释放锁的函数, 始终非 nil
+//   - error: 加锁失败时返回错误
+func (l *LogRotateX) lockRotate() (func(), error) {
+	if l.rotateLock == nil {
+		return func() {}, nil
+	}
+	if err := l.rotateLock.lock(); err != nil {
+		return func() {}, err
+	}
+	return l.rotateLock.unlock, nil
+}
+
+// lockCleanup 获取清理锁: 进程内始终互斥, 启用多进程模式时同时持有跨进程的清理锁。
+// 编号备份模式下轮转移位备份文件时同样持有该锁, 保证清理扫描到的文件名在执行期间不变。
+//
+// 返回值:
+//   - func(): 释放锁的函数, 始终非 nil
+//   - error: 加锁失败时返回错误
+func (l *LogRotateX) lockCleanup() (func(), error) {
+	l.backupMu.Lock()
+	if l.cleanupLock == nil {
+		return l.backupMu.Unlock, nil
+	}
+	if err := l.cleanupLock.lock(); err != nil {
+		l.backupMu.Unlock()
+		return func() {}, err
+	}
+	return func() {
+		l.cleanupLock.unlock()
+		l.backupMu.Unlock()
+	}, nil
+}
+
+// syncWithDisk 重新检查日志路径与当前打开文件的状态, 供多进程模式和监视模式使用。
+// 如果路径已指向其他文件或文件不存在 (被其他进程轮转或被外部移动、删除),
+// 则关闭当前句柄等待重新打开; 否则使用磁盘上的真实大小刷新 l.size,
+// 以便在文件被其他进程追加或被外部截断后仍能正确判断是否需要轮转。
+//
+// 返回值:
+//   - error: 获取文件信息失败时返回错误
+func (l *LogRotateX) syncWithDisk() error {
+	// 文件尚未打开时由 openExistingOrNew 负责读取磁盘状态
+	if l.file == nil {
+		return nil
+	}
+
+	pathInfo, err := os.Stat(l.filename())
+	if os.IsNotExist(err) {
+		// 文件已被其他进程重命名, 关闭当前句柄后重新打开
+		return l.close()
+	}
+	if err != nil {
+		return fmt.Errorf("error getting log file info: %w", err)
+	}
+
+	fdInfo, err := l.file.Stat()
+	if err != nil {
+		return fmt.Errorf("error getting open file info: %w", err)
+	}
+
+	// 路径已指向其他文件, 说明其他进程已完成轮转
+	if !os.SameFile(pathInfo, fdInfo) {
+		return l.close()
+	}
+
+	// 同一文件: 以磁盘上的大小为准 (包含其他进程写入的数据)
+	l.size = pathInfo.Size()
+	return nil
+}
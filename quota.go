@@ -0,0 +1,63 @@
+// quota.go 实现了logrotatex包的磁盘配额功能。
+// 设置 MaxTotalSize 后，每次清理时统计所有备份文件 (包括压缩文件和日期目录中的文件)
+// 与当前日志文件的总大小，超出配额时从最旧的备份文件开始删除。
+
+package logrotatex
+
+import (
+	"fmt"
+	"os"
+)
+
+// enforceTotalSize 删除最旧的备份文件, 直到备份文件与当前日志文件的总大小不超过 MaxTotalSize。
+// 在按数量、天数清理和压缩完成之后调用, 使用压缩后的实际大小统计, 调用方需持有清理锁。
+//
+// 返回值:
+//   - error: 扫描或删除文件失败时返回错误
+func (l *LogRotateX) enforceTotalSize() error {
+	if l.MaxTotalSize <= 0 {
+		return nil
+	}
+
+	// 重新扫描, 获取压缩和删除之后的文件状态
+	files, err := l.oldLogFiles()
+	if err != nil {
+		return fmt.Errorf("failed to get old log files: %w", err)
+	}
+
+	// 当前日志文件也计入配额, 但不会被删除
+	var activeSize int64
+	if info, err := os.Stat(l.filename()); err == nil {
+		activeSize = info.Size()
+	}
+
+	remove := l.getFilesOverQuota(files, activeSize)
+	if len(remove) == 0 {
+		return nil
+	}
+	return l.executeCleanup(remove, nil)
+}
+
+// getFilesOverQuota 返回超出 MaxTotalSize 配额需要删除的备份文件
+//
+// 参数:
+//   - files: 所有备份文件信息列表 (按时间从新到旧排列)
+//   - activeSize: 当前日志文件的大小
+//
+// 返回值:
+//   - []logInfo: 需要删除的备份文件列表, 从最新的超额文件开始到最旧的文件
+func (l *LogRotateX) getFilesOverQuota(files []logInfo, activeSize int64) []logInfo {
+	if l.MaxTotalSize <= 0 || len(files) == 0 {
+		return nil
+	}
+
+	// 从新到旧累加, 第一个超出配额的文件及更旧的文件全部删除
+	total := activeSize
+	for i, f := range files {
+		total += f.Size()
+		if total > int64(l.MaxTotalSize) {
+			return files[i:]
+		}
+	}
+	return nil
+}
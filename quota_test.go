@@ -0,0 +1,118 @@
+// quota_test.go 包含了磁盘配额 (MaxTotalSize) 的测试用例。
+// 该文件验证超出配额时从最旧的备份文件开始删除，当前日志文件计入配额，
+// 以及与日期目录、压缩和 MaxFiles 组合使用。
+
+package logrotatex
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// TestGetFilesOverQuota 测试按总大小计算需要删除的备份文件
+func TestGetFilesOverQuota(t *testing.T) {
+	now := time.Now()
+	files := []logInfo{
+		{timestamp: now.Add(-1 * time.Hour), FileInfo: mockFileInfo{name: "app_3.log", size: 300}},
+		{timestamp: now.Add(-2 * time.Hour), FileInfo: mockFileInfo{name: "app_2.log", size: 200}},
+		{timestamp: now.Add(-3 * time.Hour), FileInfo: mockFileInfo{name: "app_1.log", size: 100}},
+	}
+
+	tests := []struct {
+		name       string
+		maxTotal   ByteSize
+		activeSize int64
+		want       []string
+	}{
+		{"未设置配额", 0, 1000, nil},
+		{"未超出配额", 700, 100, nil},
+		{"删除最旧的文件", 600, 100, []string{"app_1.log"}},
+		{"当前文件计入配额", 600, 250, []string{"app_2.log", "app_1.log"}},
+		{"当前文件已超出配额", 100, 200, []string{"app_3.log", "app_2.log", "app_1.log"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			l := &LogRotateX{MaxTotalSize: tt.maxTotal}
+			remove := l.getFilesOverQuota(files, tt.activeSize)
+			equals(len(tt.want), len(remove), t)
+			for i, f := range remove {
+				equals(tt.want[i], f.Name(), t)
+			}
+		})
+	}
+}
+
+// TestMaxTotalSize_Rotate 测试轮转后删除最旧的备份文件直到总大小不超过配额
+func TestMaxTotalSize_Rotate(t *testing.T) {
+	originalCurrentTime := currentTime
+	originalFake := fakeCurrentTime
+	defer func() {
+		currentTime = originalCurrentTime
+		fakeCurrentTime = originalFake
+	}()
+	currentTime = fakeTime
+	fakeCurrentTime = time.Date(2026, 10, 16, 9, 0, 0, 0, time.UTC)
+
+	dir := makeTempDir("TestMaxTotalSize_Rotate", t)
+	defer func() { _ = os.RemoveAll(dir) }()
+
+	l := &LogRotateX{LogFilePath: logFile(dir), MaxBytes: 10, MaxTotalSize: 25}
+	defer func() { _ = l.Close() }()
+
+	// 每次写入 8 字节并轮转, 当前文件 8 字节 + 2 个备份 16 字节 = 24 字节
+	for i := 0; i < 4; i++ {
+		_, err := l.Write([]byte("01234567"))
+		isNil(err, t)
+		isNil(l.Rotate(), t)
+		fakeCurrentTime = fakeCurrentTime.Add(time.Minute)
+	}
+	_, err := l.Write([]byte("01234567"))
+	isNil(err, t)
+	isNil(l.cleanupSync(), t)
+
+	notExist(filepath.Join(dir, "foobar_20261016090000.log"), t)
+	notExist(filepath.Join(dir, "foobar_20261016090100.log"), t)
+	exists(filepath.Join(dir, "foobar_20261016090200.log"), t)
+	exists(filepath.Join(dir, "foobar_20261016090300.log"), t)
+	fileCount(dir, 3, t)
+}
+
+// TestMaxTotalSize_DateDirAndCompress 测试日期目录中的压缩文件按压缩后的大小计入配额, 并与 MaxFiles 组合使用
+func TestMaxTotalSize_DateDirAndCompress(t *testing.T) {
+	dir := makeTempDir("TestMaxTotalSize_DateDirAndCompress", t)
+	defer func() { _ = os.RemoveAll(dir) }()
+
+	l := &LogRotateX{
+		LogFilePath:   logFile(dir),
+		DateDirLayout: true,
+		MaxFiles:      3,
+		MaxTotalSize:  250,
+	}
+	defer func() { _ = l.Close() }()
+
+	// 日期目录中的备份文件和压缩文件, 各 100 字节
+	old := []string{
+		filepath.Join(dir, "2026-10-13", "foobar_20261013090000.zip"),
+		filepath.Join(dir, "2026-10-14", "foobar_20261014090000.log"),
+		filepath.Join(dir, "2026-10-15", "foobar_20261015090000.zip"),
+		filepath.Join(dir, "2026-10-16", "foobar_20261016090000.log"),
+	}
+	for _, name := range old {
+		isNil(os.MkdirAll(filepath.Dir(name), 0755), t)
+		isNil(os.WriteFile(name, make([]byte, 100), 0600), t)
+	}
+
+	_, err := l.Write([]byte("data"))
+	isNil(err, t)
+	isNil(l.cleanupSync(), t)
+
+	// MaxFiles 删除最旧的一个, 配额再删除一个
+	notExist(old[0], t)
+	notExist(old[1], t)
+	notExist(filepath.Dir(old[1]), t)
+	exists(old[2], t)
+	exists(old[3], t)
+}
@@ -0,0 +1,240 @@
+// reload.go 实现了logrotatex包的运行时配置热更新功能。
+// 直接修改运行中实例的字段会与 Write 和异步清理协程产生数据竞争，ApplyConfig 在持有 l.mu 和清理锁的情况下
+// 一次性替换所有可热更新的字段，保留规则收紧时立即执行一轮清理。
+// WatchConfig 定期检查配置文件的修改时间和大小，变化后重新加载并应用，无效的配置被拒绝并通过 ErrorHandler 报告。
+
+package logrotatex
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"gitee.com/MM-Q/comprx"
+)
+
+// errNotReloadable 是运行时不能修改的字段的错误原因
+var errNotReloadable = errors.New("cannot be changed at runtime")
+
+// ApplyConfig 将 cfg 中可热更新的字段原子地应用到运行中的实例, 可以与 Write 并发调用。
+// cfg 通常来自 LoadConfig 或 FromConfig, 应用前会调用 cfg.Validate 校验。
+//
+// 可热更新的字段:
+//   - 大小与保留: MaxSize, MaxBytes, MaxTotalSize, MaxAge, MaxFiles, MinFreeSpace, MinFreePercent, FreeSpaceCheckInterval
+//   - 压缩: Compress
+//   - 轮转: RotateByDay, RotateSchedule, RotateMode, LocalTime
+//   - 其他: LinkName, BackupLinkName (下次打开文件时生效), WatchFile, WatchInterval, DiskFullPolicy
+//
+// LogFilePath (为空时表示不变)、Async、DateDirLayout、NumberedBackups、BackgroundRotate、CleanupInterval、MultiProcess、
+// CompressType (为空时表示 zip) 影响已打开的文件、备份文件的识别或后台协程, 与当前值不同时整个配置被拒绝。
+// 备份文件按当前压缩类型的扩展名识别, 修改 CompressType 后已有的压缩备份将不再受保留规则管理。
+// 回调、Namer、FallbackWriter 等不能写入配置文件的字段不会被修改。
+//
+// MaxFiles、MaxAge、MaxTotalSize 或剩余空间下限收紧, 或启用压缩时, 按 Async 配置立即执行一轮清理。
+//
+// 参数:
+//   - cfg: 新的配置
+//
+// 返回值:
+//   - error: 配置无效时返回 ConfigErrors, 配置未被应用; 实例已关闭时返回错误
+func (l *LogRotateX) ApplyConfig(cfg *LogRotateX) error {
+	if cfg == nil {
+		return errors.New("config cannot be nil")
+	}
+	errs := cfg.validate()
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.closed.Load() {
+		return errors.New("apply config on closed")
+	}
+	if err := l.initDefaults(); err != nil {
+		return err
+	}
+
+	// 压缩类型为空时表示默认的 zip
+	compressType := cfg.CompressType
+	if compressType.String() == "" {
+		compressType = comprx.CompressTypeZip
+	}
+
+	// 不能热更新的字段
+	for _, f := range []struct {
+		name    string
+		value   any
+		changed bool
+	}{
+		{"LogFilePath", cfg.LogFilePath, cfg.LogFilePath != "" && filepath.Clean(cfg.LogFilePath) != l.LogFilePath},
+		{"Async", cfg.Async, cfg.Async != l.Async},
+		{"DateDirLayout", cfg.DateDirLayout, cfg.DateDirLayout != l.DateDirLayout},
+		{"NumberedBackups", cfg.NumberedBackups, cfg.NumberedBackups != l.NumberedBackups},
+		{"BackgroundRotate", cfg.BackgroundRotate, cfg.BackgroundRotate != l.BackgroundRotate},
+		{"CleanupInterval", cfg.CleanupInterval, cfg.CleanupInterval != l.CleanupInterval},
+		{"MultiProcess", cfg.MultiProcess, cfg.MultiProcess != l.MultiProcess},
+		{"CompressType", cfg.CompressType, compressType != l.CompressType},
+	} {
+		if f.changed {
+			errs = append(errs, &FieldError{Field: f.name, Value: fmt.Sprint(f.value), Err: errNotReloadable})
+		}
+	}
+	if len(errs) > 0 {
+		return errs
+	}
+
+	// 已校验, 解析不会失败
+	schedule, _ := parseSchedule(cfg.RotateSchedule)
+	tightened := retentionTightened(l, cfg)
+
+	// 同时持有清理锁, 清理协程在清理锁内读取保留规则
+	l.backupMu.Lock()
+	l.MaxSize = cfg.MaxSize
+	l.MaxBytes = cfg.MaxBytes
+	l.MaxTotalSize = cfg.MaxTotalSize
+	l.MaxAge = cfg.MaxAge
+	l.MaxFiles = cfg.MaxFiles
+	l.MinFreeSpace = cfg.MinFreeSpace
+	l.MinFreePercent = cfg.MinFreePercent
+	l.FreeSpaceCheckInterval = cfg.FreeSpaceCheckInterval
+	l.Compress = cfg.Compress
+	// 时区变化后文件名模式的展开结果可能不同, 下次写入时重新检查
+	if cfg.LocalTime != l.LocalTime {
+		l.patternSwitch = time.Time{}
+	}
+	l.LocalTime = cfg.LocalTime
+	l.RotateMode = cfg.RotateMode
+	l.LinkName = cfg.LinkName
+	l.BackupLinkName = cfg.BackupLinkName
+	l.WatchFile = cfg.WatchFile
+	l.WatchInterval = cfg.WatchInterval
+	l.DiskFullPolicy = cfg.DiskFullPolicy
+
+	// 重新启用按天轮转时从当前日期开始计算, 避免以很久之前的日期立即轮转
+	if cfg.RotateByDay && !l.RotateByDay {
+		l.lastRotationDate = l.now()
+	}
+	l.RotateByDay = cfg.RotateByDay
+
+	// 定时计划变化时重新计算下一个边界
+	if cfg.RotateSchedule != l.RotateSchedule {
+		l.RotateSchedule = cfg.RotateSchedule
+		l.schedule = schedule
+		l.nextRotation = time.Time{}
+	}
+	l.applyFieldDefaults()
+	l.backupMu.Unlock()
+
+	if !tightened {
+		return nil
+	}
+	if l.Async {
+		l.cleanupAsync()
+	} else if err := l.cleanupSync(); err != nil {
+		l.reportError(OpCleanup, l.dir(), err)
+	}
+	return nil
+}
+
+// retentionTightened 检查新配置是否收紧了保留规则或新启用了压缩, 需要立即清理
+func retentionTightened(old, cfg *LogRotateX) bool {
+	tighter := func(oldLimit, newLimit int64) bool {
+		return newLimit > 0 && (oldLimit <= 0 || newLimit < oldLimit)
+	}
+	return tighter(int64(old.MaxFiles), int64(cfg.MaxFiles)) ||
+		tighter(int64(old.MaxAge), int64(cfg.MaxAge)) ||
+		tighter(int64(old.MaxTotalSize), int64(cfg.MaxTotalSize)) ||
+		cfg.MinFreeSpace > old.MinFreeSpace ||
+		cfg.MinFreePercent > old.MinFreePercent ||
+		(cfg.Compress && !old.Compress)
+}
+
+// WatchConfig 定期检查配置文件, 修改时间或大小变化后通过 LoadConfig 重新加载并调用 ApplyConfig。
+// 加载失败或配置被拒绝时以 *OpError (Op 为 OpConfig) 报告给 ErrorHandler, 实例保持原配置继续运行。
+// 配置中的 buffer 小节被忽略。启动时只记录文件的当前状态, 不会立即应用。
+// 文件中未写出的字段取 NewLogRotateX 的默认值, 因此被监视的实例通常应由 LoadConfig 从同一文件创建,
+// 否则 DateDirLayout 等不能热更新的字段可能不一致导致配置被拒绝。
+//
+// 参数:
+//   - path: 配置文件路径, 格式由扩展名决定
+//   - interval: 检查间隔, 小于等于 0 时为 1 秒
+//
+// 返回值:
+//   - func(): 停止监视的函数, 会等待监视协程退出, 可重复调用; Close 也会停止监视并等待监视协程退出
+//   - error: 无法读取配置文件的状态或实例已关闭时返回错误
+func (l *LogRotateX) WatchConfig(path string, interval time.Duration) (func(), error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to watch config file: %w", err)
+	}
+	if interval <= 0 {
+		interval = defaultWatchInterval
+	}
+
+	// 在锁内登记停止信号, 与 Close 互斥: Close 关闭信号后在 l.wg 上等待监视协程退出
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.closed.Load() {
+		return nil, errors.New("watch config on closed")
+	}
+	done := make(chan struct{})
+	exited := make(chan struct{})
+	l.configWatchers = append(l.configWatchers, done)
+
+	l.wg.Go(func() {
+		defer close(exited)
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		modTime, size := info.ModTime(), info.Size()
+		for {
+			select {
+			case <-done:
+				return
+			case <-ticker.C:
+			}
+
+			info, err := os.Stat(path)
+			if err != nil {
+				// 编辑器保存时可能短暂地删除文件, 下次检查时重试
+				continue
+			}
+			if info.ModTime().Equal(modTime) && info.Size() == size {
+				continue
+			}
+			modTime, size = info.ModTime(), info.Size()
+
+			if err := l.reloadConfig(path); err != nil && !l.closed.Load() {
+				l.reportError(OpConfig, path, err)
+			}
+		}
+	})
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			// 信号已被 Close 关闭时不在实例中登记
+			l.mu.Lock()
+			for i, ch := range l.configWatchers {
+				if ch == done {
+					l.configWatchers = append(l.configWatchers[:i], l.configWatchers[i+1:]...)
+					close(done)
+					break
+				}
+			}
+			l.mu.Unlock()
+			<-exited
+		})
+	}, nil
+}
+
+// reloadConfig 重新加载配置文件并应用
+func (l *LogRotateX) reloadConfig(path string) error {
+	cfg, _, err := LoadConfig(path)
+	if err != nil {
+		return err
+	}
+	return l.ApplyConfig(cfg)
+}
@@ -0,0 +1,181 @@
+// reload_test.go 包含了运行时配置热更新 (ApplyConfig/WatchConfig) 的测试用例。
+// 该文件验证保留规则收紧后立即清理，不能热更新的字段被拒绝且配置保持不变，
+// 以及监视的配置文件变化后重新加载，无效配置通过 ErrorHandler 报告。
+
+package logrotatex
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"gitee.com/MM-Q/comprx"
+)
+
+// TestApplyConfig 测试收紧 MaxFiles 后立即清理多余的备份
+func TestApplyConfig(t *testing.T) {
+	originalCurrentTime := currentTime
+	originalFake := fakeCurrentTime
+	defer func() {
+		currentTime = originalCurrentTime
+		fakeCurrentTime = originalFake
+	}()
+	currentTime = fakeTime
+
+	dir := makeTempDir("TestApplyConfig", t)
+	defer func() { _ = os.RemoveAll(dir) }()
+
+	l := &LogRotateX{LogFilePath: logFile(dir)}
+	defer func() { _ = l.Close() }()
+
+	for i := 0; i < 3; i++ {
+		_, err := l.Write([]byte("boo!"))
+		isNil(err, t)
+		newFakeTime()
+		isNil(l.Rotate(), t)
+	}
+	fileCount(dir, 4, t)
+
+	isNil(l.ApplyConfig(&LogRotateX{MaxFiles: 1, MaxSize: 5}), t)
+	equals(1, l.MaxFiles, t)
+	equals(5, l.MaxSize, t)
+	fileCount(dir, 2, t)
+
+	// 零值恢复为默认值
+	isNil(l.ApplyConfig(&LogRotateX{}), t)
+	equals(defaultMaxSize, l.MaxSize, t)
+	equals(RotateModeRename, l.RotateMode, t)
+}
+
+// TestApplyConfig_Rejected 测试无效配置和修改不能热更新的字段时整个配置被拒绝
+func TestApplyConfig_Rejected(t *testing.T) {
+	dir := makeTempDir("TestApplyConfig_Rejected", t)
+	defer func() { _ = os.RemoveAll(dir) }()
+
+	l := &LogRotateX{LogFilePath: logFile(dir), MaxFiles: 3}
+	defer func() { _ = l.Close() }()
+
+	err := l.ApplyConfig(&LogRotateX{LogFilePath: filepath.Join(dir, "other.log"), Async: true, MaxFiles: 1, MaxAge: -1,
+		CompressType: comprx.CompressTypeGz})
+	var errs ConfigErrors
+	if !errors.As(err, &errs) {
+		t.Fatalf("期望错误类型为 ConfigErrors, 实际: %v", err)
+	}
+	var fields []string
+	for _, e := range errs {
+		fields = append(fields, e.Field)
+	}
+	equals([]string{"MaxAge", "LogFilePath", "Async", "CompressType"}, fields, t)
+	if !errors.Is(err, errNotReloadable) {
+		t.Fatal("期望 errors.Is 能判断不能热更新的字段")
+	}
+	equals(3, l.MaxFiles, t)
+
+	// 相同的路径 (未清理的写法) 和默认的压缩类型可以应用
+	isNil(l.ApplyConfig(&LogRotateX{LogFilePath: dir + "/./" + filepath.Base(logFile(dir)), MaxFiles: 1,
+		CompressType: comprx.CompressTypeZip}), t)
+	equals(1, l.MaxFiles, t)
+
+	isNil(l.Close(), t)
+	if err := l.ApplyConfig(&LogRotateX{}); err == nil {
+		t.Fatal("期望关闭后应用配置返回错误")
+	}
+}
+
+// TestWatchConfig 测试配置文件变化后重新加载, 无效配置被报告且不影响当前配置
+func TestWatchConfig(t *testing.T) {
+	dir := makeTempDir("TestWatchConfig", t)
+	defer func() { _ = os.RemoveAll(dir) }()
+
+	path := filepath.Join(dir, "log.toml")
+	isNil(os.WriteFile(path, []byte("maxfiles = 3\n"), 0644), t)
+
+	// 被监视的实例从同一配置文件创建
+	l, _, err := LoadConfig(path)
+	isNil(err, t)
+	l.LogFilePath = logFile(dir)
+	reported := make(chan error, 10)
+	l.ErrorHandler = func(err error) { reported <- err }
+	defer func() { _ = l.Close() }()
+
+	stop, err := l.WatchConfig(path, 10*time.Millisecond)
+	isNil(err, t)
+	defer stop()
+
+	// 无效配置通过 ErrorHandler 报告
+	isNil(os.WriteFile(path, []byte("maxfiles = -1\n"), 0644), t)
+	select {
+	case err := <-reported:
+		var opErr *OpError
+		if !errors.As(err, &opErr) || opErr.Op != OpConfig || opErr.Path != path {
+			t.Fatalf("期望 OpConfig 错误, 实际: %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("无效配置未通过 ErrorHandler 报告")
+	}
+
+	// 有效配置被应用
+	isNil(os.WriteFile(path, []byte("maxfiles = 10\n"), 0644), t)
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		l.mu.Lock()
+		maxFiles := l.MaxFiles
+		l.mu.Unlock()
+		if maxFiles == 10 {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("配置未被重新加载, MaxFiles: %d", maxFiles)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	stop()
+	stop()
+
+	if _, err := l.WatchConfig(filepath.Join(dir, "missing.toml"), 0); err == nil {
+		t.Fatal("期望配置文件不存在时返回错误")
+	}
+}
+
+// TestWatchConfig_Close 测试 Close 停止监视协程, 关闭后配置文件的变化不再被处理或报告
+func TestWatchConfig_Close(t *testing.T) {
+	dir := makeTempDir("TestWatchConfig_Close", t)
+	defer func() { _ = os.RemoveAll(dir) }()
+
+	path := filepath.Join(dir, "log.toml")
+	isNil(os.WriteFile(path, []byte("maxfiles = 3\n"), 0644), t)
+
+	l, _, err := LoadConfig(path)
+	isNil(err, t)
+	l.LogFilePath = logFile(dir)
+	reported := make(chan error, 10)
+	l.ErrorHandler = func(err error) { reported <- err }
+	events := l.Events()
+
+	stop, err := l.WatchConfig(path, 5*time.Millisecond)
+	isNil(err, t)
+	isNil(l.Close(), t)
+
+	// 监视协程已退出, 事件通道已关闭
+	l.mu.Lock()
+	equals(0, len(l.configWatchers), t)
+	l.mu.Unlock()
+	for range events {
+	}
+
+	isNil(os.WriteFile(path, []byte("maxfiles = -1\n"), 0644), t)
+	time.Sleep(50 * time.Millisecond)
+	select {
+	case err := <-reported:
+		t.Fatalf("关闭后不应报告配置错误, 实际: %v", err)
+	default:
+	}
+
+	stop()
+	if _, err := l.WatchConfig(path, 0); err == nil {
+		t.Fatal("期望关闭后监视配置文件返回错误")
+	}
+}
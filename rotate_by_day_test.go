@@ -328,3 +328,96 @@ func newFakeDay() {
 	// 将模拟的当前时间增加一天
 	fakeCurrentTime = fakeCurrentTime.Add(time.Hour * 24)
 }
+
+// TestRotateByDay_Restart 测试服务重启时已有日志文件属于前一天会立即轮转,
+// 备份文件以文件的实际日期命名
+func TestRotateByDay_Restart(t *testing.T) {
+	originalCurrentTime := currentTime
+	originalFake := fakeCurrentTime
+	defer func() {
+		currentTime = originalCurrentTime
+		fakeCurrentTime = originalFake
+	}()
+	currentTime = fakeTime
+	fakeCurrentTime = time.Date(2026, 10, 16, 9, 0, 0, 0, time.UTC)
+
+	dir := makeTempDir("TestRotateByDay_Restart", t)
+	defer func() { _ = os.RemoveAll(dir) }()
+
+	// 模拟前一天写入的日志文件
+	yesterday := time.Date(2026, 10, 15, 23, 30, 0, 0, time.UTC)
+	isNil(os.WriteFile(logFile(dir), []byte("yesterday\n"), 0600), t)
+	isNil(os.Chtimes(logFile(dir), yesterday, yesterday), t)
+
+	l := &LogRotateX{LogFilePath: logFile(dir), RotateByDay: true}
+	defer func() { _ = l.Close() }()
+
+	_, err := l.Write([]byte("today\n"))
+	isNil(err, t)
+
+	existsWithContent(filepath.Join(dir, "foobar_20261015233000.log"), []byte("yesterday\n"), t)
+	existsWithContent(logFile(dir), []byte("today\n"), t)
+	fileCount(dir, 2, t)
+
+	// 同一天内继续写入不再轮转
+	fakeCurrentTime = time.Date(2026, 10, 16, 18, 0, 0, 0, time.UTC)
+	_, err = l.Write([]byte("evening\n"))
+	isNil(err, t)
+	fileCount(dir, 2, t)
+}
+
+// TestRotateByDay_RestartSameDay 测试重启时已有日志文件属于当天则继续追加
+func TestRotateByDay_RestartSameDay(t *testing.T) {
+	originalCurrentTime := currentTime
+	originalFake := fakeCurrentTime
+	defer func() {
+		currentTime = originalCurrentTime
+		fakeCurrentTime = originalFake
+	}()
+	currentTime = fakeTime
+	fakeCurrentTime = time.Date(2026, 10, 16, 9, 0, 0, 0, time.UTC)
+
+	dir := makeTempDir("TestRotateByDay_RestartSameDay", t)
+	defer func() { _ = os.RemoveAll(dir) }()
+
+	earlier := time.Date(2026, 10, 16, 1, 0, 0, 0, time.UTC)
+	isNil(os.WriteFile(logFile(dir), []byte("earlier\n"), 0600), t)
+	isNil(os.Chtimes(logFile(dir), earlier, earlier), t)
+
+	l := &LogRotateX{LogFilePath: logFile(dir), RotateByDay: true}
+	defer func() { _ = l.Close() }()
+
+	_, err := l.Write([]byte("later\n"))
+	isNil(err, t)
+
+	existsWithContent(logFile(dir), []byte("earlier\nlater\n"), t)
+	fileCount(dir, 1, t)
+}
+
+// TestRotateByDay_RestartWithDateDirLayout 测试重启轮转的备份文件放入文件实际日期的目录
+func TestRotateByDay_RestartWithDateDirLayout(t *testing.T) {
+	originalCurrentTime := currentTime
+	originalFake := fakeCurrentTime
+	defer func() {
+		currentTime = originalCurrentTime
+		fakeCurrentTime = originalFake
+	}()
+	currentTime = fakeTime
+	fakeCurrentTime = time.Date(2026, 10, 16, 9, 0, 0, 0, time.UTC)
+
+	dir := makeTempDir("TestRotateByDay_RestartWithDateDirLayout", t)
+	defer func() { _ = os.RemoveAll(dir) }()
+
+	yesterday := time.Date(2026, 10, 15, 12, 0, 0, 0, time.UTC)
+	isNil(os.WriteFile(logFile(dir), []byte("yesterday\n"), 0600), t)
+	isNil(os.Chtimes(logFile(dir), yesterday, yesterday), t)
+
+	l := &LogRotateX{LogFilePath: logFile(dir), RotateByDay: true, DateDirLayout: true}
+	defer func() { _ = l.Close() }()
+
+	_, err := l.Write([]byte("today\n"))
+	isNil(err, t)
+
+	existsWithContent(filepath.Join(dir, "2026-10-15", "foobar_20261015120000.log"), []byte("yesterday\n"), t)
+	existsWithContent(logFile(dir), []byte("today\n"), t)
+}
@@ -0,0 +1,300 @@
+// schedule.go 实现了logrotatex包的定时轮转计划。
+// 该文件解析 RotateSchedule 配置 (固定间隔、预定义描述符或五段式 cron 表达式)，
+// 并根据当前时间计算下一个轮转边界，供写入路径和后台调度使用。
+
+package logrotatex
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// rotateSchedule 是轮转计划, 用于计算给定时间之后的下一个轮转边界
+type rotateSchedule interface {
+	// next 返回严格晚于 t 的下一个轮转时间, 使用 t 所在的时区; 无法计算时返回零值
+	next(t time.Time) time.Time
+}
+
+// scheduleDescriptors 是预定义的轮转计划描述符
+var scheduleDescriptors = map[string]string{
+	"@hourly":   "0 * * * *",
+	"@daily":    "0 0 * * *",
+	"@midnight": "0 0 * * *",
+	"@weekly":   "0 0 * * 0",
+	"@monthly":  "0 0 1 * *",
+	"@yearly":   "0 0 1 1 *",
+	"@annually": "0 0 1 1 *",
+}
+
+// parseSchedule 解析轮转计划配置
+//
+// 支持的格式:
+//   - 固定间隔: "1h", "30m", "@every 6h" (按当天零点对齐, 必须能整除 24h)
+//   - 预定义描述符: "@hourly", "@daily", "@weekly", "@monthly", "@yearly"
+//   - 五段式 cron 表达式: "分 时 日 月 周", 例如 "0 4 * * *" 表示每天 04:00
+//
+// 参数:
+//   - spec: 轮转计划配置, 为空时返回 nil
+//
+// 返回值:
+//   - rotateSchedule: 轮转计划
+//   - error: 配置无效时返回错误
+func parseSchedule(spec string) (rotateSchedule, error) {
+	spec = strings.TrimSpace(spec)
+	if spec == "" {
+		return nil, nil
+	}
+
+	// 预定义描述符
+	if expr, ok := scheduleDescriptors[strings.ToLower(spec)]; ok {
+		return parseCron(expr)
+	}
+
+	// 固定间隔
+	if after, ok := strings.CutPrefix(spec, "@every "); ok {
+		spec = strings.TrimSpace(after)
+	}
+	if d, err := time.ParseDuration(spec); err == nil {
+		if d < time.Second {
+			return nil, fmt.Errorf("invalid rotate schedule %q: interval must be at least 1s", spec)
+		}
+		// 边界按当天零点对齐, 不能整除一天的间隔会在零点前后产生长度不同的周期
+		if (24*time.Hour)%d != 0 {
+			return nil, fmt.Errorf("invalid rotate schedule %q: interval must divide 24h evenly", spec)
+		}
+		return intervalSchedule(d), nil
+	}
+
+	// cron 表达式
+	return parseCron(spec)
+}
+
+// intervalSchedule 是固定间隔的轮转计划, 边界从当天零点开始按间隔对齐, 间隔能整除 24h 保证每个周期长度相同
+type intervalSchedule time.Duration
+
+// next 返回下一个按间隔对齐的轮转时间
+func (s intervalSchedule) next(t time.Time) time.Time {
+	d := time.Duration(s)
+	year, month, day := t.Date()
+	midnight := time.Date(year, month, day, 0, 0, 0, 0, t.Location())
+	return midnight.Add((t.Sub(midnight)/d + 1) * d)
+}
+
+// cronSchedule 是五段式 cron 表达式的轮转计划, 每个字段使用位集合表示允许的取值
+type cronSchedule struct {
+	minute, hour, dom, month, dow uint64
+	domStar, dowStar              bool // 日和周字段是否为 "*", 决定两者的组合方式
+}
+
+// cronField 描述 cron 表达式中单个字段的取值范围
+type cronField struct {
+	name     string
+	min, max int
+}
+
+// cronFields 是五个字段的取值范围, 周字段允许 7 表示周日
+var cronFields = [5]cronField{
+	{"minute", 0, 59},
+	{"hour", 0, 23},
+	{"day of month", 1, 31},
+	{"month", 1, 12},
+	{"day of week", 0, 7},
+}
+
+// parseCron 解析五段式 cron 表达式
+//
+// 参数:
+//   - expr: cron 表达式, 每个字段支持 "*", 数字, 范围 "a-b", 列表 "a,b" 和步长 "*/n"
+//
+// 返回值:
+//   - *cronSchedule: 轮转计划
+//   - error: 表达式无效时返回错误
+func parseCron(expr string) (*cronSchedule, error) {
+	parts := strings.Fields(expr)
+	if len(parts) != len(cronFields) {
+		return nil, fmt.Errorf("invalid rotate schedule %q: expected %d fields, got %d", expr, len(cronFields), len(parts))
+	}
+
+	var bits [5]uint64
+	for i, part := range parts {
+		b, err := parseCronField(part, cronFields[i])
+		if err != nil {
+			return nil, fmt.Errorf("invalid rotate schedule %q: %w", expr, err)
+		}
+		bits[i] = b
+	}
+
+	// 周字段中的 7 与 0 等价, 都表示周日
+	if bits[4]&(1<<7) != 0 {
+		bits[4] = bits[4]&^(1<<7) | 1
+	}
+
+	c := &cronSchedule{
+		minute:  bits[0],
+		hour:    bits[1],
+		dom:     bits[2],
+		month:   bits[3],
+		dow:     bits[4],
+		domStar: parts[2] == "*",
+		dowStar: parts[4] == "*",
+	}
+
+	// 只按日期匹配时, 日期必须在允许的月份中存在 (如 "0 0 31 2 *" 永远不会触发)
+	if !c.domStar && c.dowStar && !c.hasValidDate() {
+		return nil, fmt.Errorf("invalid rotate schedule %q: day of month never occurs in the allowed months", expr)
+	}
+	return c, nil
+}
+
+// parseCronField 解析 cron 表达式中的单个字段
+//
+// 参数:
+//   - field: 字段内容
+//   - f: 字段的取值范围
+//
+// 返回值:
+//   - uint64: 允许取值的位集合
+//   - error: 字段无效时返回错误
+func parseCronField(field string, f cronField) (uint64, error) {
+	var bits uint64
+	for _, item := range strings.Split(field, ",") {
+		rangePart, stepPart, hasStep := strings.Cut(item, "/")
+
+		step := 1
+		if hasStep {
+			n, err := strconv.Atoi(stepPart)
+			if err != nil || n <= 0 {
+				return 0, fmt.Errorf("invalid step %q in %s field", stepPart, f.name)
+			}
+			step = n
+		}
+
+		// 解析范围: "*", "a" 或 "a-b"
+		lo, hi := f.min, f.max
+		if rangePart != "*" {
+			loStr, hiStr, isRange := strings.Cut(rangePart, "-")
+			var err error
+			if lo, err = strconv.Atoi(loStr); err != nil {
+				return 0, fmt.Errorf("invalid value %q in %s field", loStr, f.name)
+			}
+			hi = lo
+			if isRange {
+				if hi, err = strconv.Atoi(hiStr); err != nil {
+					return 0, fmt.Errorf("invalid value %q in %s field", hiStr, f.name)
+				}
+			} else if hasStep {
+				// "a/n" 表示从 a 开始到最大值, 每 n 个取一次
+				hi = f.max
+			}
+		}
+
+		if lo < f.min || hi > f.max || lo > hi {
+			return 0, fmt.Errorf("value %q out of range [%d, %d] in %s field", rangePart, f.min, f.max, f.name)
+		}
+
+		for v := lo; v <= hi; v += step {
+			bits |= 1 << uint(v)
+		}
+	}
+	return bits, nil
+}
+
+// allHours 是小时字段为 "*" 时的位集合
+const allHours = 1<<24 - 1
+
+// next 返回下一个匹配 cron 表达式的整分钟时间, 最多向后查找 5 年。
+//
+// 小时和分钟按绝对时间前进, 避免 time.Date 在夏令时跳过的时段内归一化到更早的时间导致无法前进。
+// 与常见的 cron 实现一致, 固定小时的计划在夏令时切换时:
+//   - 开始时被跳过的时间 (如 02:30) 在跳过后的第一个时刻轮转
+//   - 结束时重复的时段只在第一次经过时轮转
+func (c *cronSchedule) next(t time.Time) time.Time {
+	loc := t.Location()
+	from := wallClock(t)
+	t = t.Truncate(time.Minute).Add(time.Minute)
+	limit := t.AddDate(5, 0, 0)
+
+	for t.Before(limit) {
+		year, month, day := t.Date()
+
+		// 从大到小逐级匹配, 不匹配时跳到下一个单位的起点
+		var next time.Time
+		switch {
+		case c.month&(1<<uint(month)) == 0:
+			next = time.Date(year, month+1, 1, 0, 0, 0, 0, loc)
+		case !c.dayMatches(t):
+			next = time.Date(year, month, day+1, 0, 0, 0, 0, loc)
+		case c.hour&(1<<uint(t.Hour())) == 0:
+			next = nextHour(t)
+		case c.minute&(1<<uint(t.Minute())) == 0:
+			next = t.Add(time.Minute)
+		case c.hour != allHours && !wallClock(t).After(from):
+			// 夏令时结束后重复的时段, 已经在第一次经过时匹配过
+			next = t.Add(time.Minute)
+		default:
+			return t
+		}
+
+		// 当天零点不存在 (零点切换夏令时的时区) 时 time.Date 可能不前进, 改为按小时前进
+		if !next.After(t) {
+			next = nextHour(t)
+		}
+		if c.hour != allHours && c.skippedMatch(t, next) {
+			return next
+		}
+		t = next
+	}
+	return time.Time{}
+}
+
+// nextHour 返回 t 之后下一个整点, 按绝对时间前进
+func nextHour(t time.Time) time.Time {
+	return t.Add(time.Duration(60-t.Minute()) * time.Minute)
+}
+
+// wallClock 返回 t 的本地时钟读数 (以 UTC 表示), 用于比较夏令时切换前后的钟面时间
+func wallClock(t time.Time) time.Time {
+	year, month, day := t.Date()
+	return time.Date(year, month, day, t.Hour(), t.Minute(), t.Second(), t.Nanosecond(), time.UTC)
+}
+
+// skippedMatch 检查从 prev 前进到 next 时, 夏令时开始跳过的钟面时间中是否有匹配的时间
+func (c *cronSchedule) skippedMatch(prev, next time.Time) bool {
+	end := wallClock(next)
+	for w := wallClock(prev).Add(next.Sub(prev)); w.Before(end); w = w.Add(time.Minute) {
+		if c.month&(1<<uint(w.Month())) != 0 && c.dayMatches(w) &&
+			c.hour&(1<<uint(w.Hour())) != 0 && c.minute&(1<<uint(w.Minute())) != 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// hasValidDate 检查日期字段是否能在允许的月份中匹配到存在的日期 (2 月按闰年计算)
+func (c *cronSchedule) hasValidDate() bool {
+	daysInMonth := [13]int{0, 31, 29, 31, 30, 31, 30, 31, 31, 30, 31, 30, 31}
+	for m := 1; m <= 12; m++ {
+		if c.month&(1<<uint(m)) == 0 {
+			continue
+		}
+		for d := 1; d <= daysInMonth[m]; d++ {
+			if c.dom&(1<<uint(d)) != 0 {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// dayMatches 检查日期是否匹配日和周字段。
+// 与标准 cron 一致: 两个字段都有限制时满足任一即可, 否则两者都需满足。
+func (c *cronSchedule) dayMatches(t time.Time) bool {
+	domMatch := c.dom&(1<<uint(t.Day())) != 0
+	dowMatch := c.dow&(1<<uint(t.Weekday())) != 0
+	if c.domStar || c.dowStar {
+		return domMatch && dowMatch
+	}
+	return domMatch || dowMatch
+}
@@ -0,0 +1,175 @@
+// schedule_test.go 包含了定时轮转计划 (RotateSchedule) 的测试用例。
+// 该文件测试了间隔、预定义描述符和 cron 表达式的解析与下一个边界的计算，
+// 以及写入时到达边界触发轮转的行为。
+
+package logrotatex
+
+import (
+	"os"
+	"testing"
+	"time"
+	_ "time/tzdata" // 夏令时测试需要的时区数据
+)
+
+// TestParseSchedule_Next 测试各种轮转计划计算出的下一个边界
+func TestParseSchedule_Next(t *testing.T) {
+	// 2026-10-16 是周五
+	base := time.Date(2026, 10, 16, 9, 15, 30, 0, time.UTC)
+
+	tests := []struct {
+		spec string
+		from time.Time
+		want time.Time
+	}{
+		{"@hourly", base, time.Date(2026, 10, 16, 10, 0, 0, 0, time.UTC)},
+		{"@daily", base, time.Date(2026, 10, 17, 0, 0, 0, 0, time.UTC)},
+		{"@weekly", base, time.Date(2026, 10, 18, 0, 0, 0, 0, time.UTC)},
+		{"@monthly", base, time.Date(2026, 11, 1, 0, 0, 0, 0, time.UTC)},
+		{"@yearly", base, time.Date(2027, 1, 1, 0, 0, 0, 0, time.UTC)},
+		{"0 4 * * *", base, time.Date(2026, 10, 17, 4, 0, 0, 0, time.UTC)},
+		{"0 4 * * *", time.Date(2026, 10, 16, 3, 59, 0, 0, time.UTC), time.Date(2026, 10, 16, 4, 0, 0, 0, time.UTC)},
+		{"0 4 * * *", time.Date(2026, 10, 16, 4, 0, 0, 0, time.UTC), time.Date(2026, 10, 17, 4, 0, 0, 0, time.UTC)},
+		{"*/15 * * * *", base, time.Date(2026, 10, 16, 9, 30, 0, 0, time.UTC)},
+		{"0 0 1 * *", time.Date(2026, 12, 15, 0, 0, 0, 0, time.UTC), time.Date(2027, 1, 1, 0, 0, 0, 0, time.UTC)},
+		{"0 0 31 * *", time.Date(2026, 11, 1, 0, 0, 0, 0, time.UTC), time.Date(2026, 12, 31, 0, 0, 0, 0, time.UTC)},
+		{"30 2 * * 1-5", base, time.Date(2026, 10, 19, 2, 30, 0, 0, time.UTC)},
+		{"0 0 * * 7", base, time.Date(2026, 10, 18, 0, 0, 0, 0, time.UTC)},
+		{"0 0 1 * 1", base, time.Date(2026, 10, 19, 0, 0, 0, 0, time.UTC)}, // 日和周都有限制时满足任一即可
+		{"0 0,12 * * *", base, time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC)},
+		{"1h", base, time.Date(2026, 10, 16, 10, 0, 0, 0, time.UTC)},
+		{"30m", base, time.Date(2026, 10, 16, 9, 30, 0, 0, time.UTC)},
+		{"@every 6h", base, time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.spec, func(t *testing.T) {
+			s, err := parseSchedule(tt.spec)
+			if err != nil {
+				t.Fatalf("解析 %q 失败: %v", tt.spec, err)
+			}
+			if got := s.next(tt.from); !got.Equal(tt.want) {
+				t.Errorf("%q 从 %v 开始的下一个边界 = %v, 期望 %v", tt.spec, tt.from, got, tt.want)
+			}
+		})
+	}
+}
+
+// TestParseSchedule_LocalTime 测试边界按时间所在的时区计算
+func TestParseSchedule_LocalTime(t *testing.T) {
+	loc := time.FixedZone("UTC+8", 8*3600)
+	s, err := parseSchedule("0 4 * * *")
+	isNil(err, t)
+
+	from := time.Date(2026, 10, 16, 3, 0, 0, 0, loc)
+	equals(time.Date(2026, 10, 16, 4, 0, 0, 0, loc), s.next(from), t)
+}
+
+// TestParseSchedule_DST 测试夏令时切换: 跳过的时间在切换后立即轮转, 重复的时段只轮转一次
+func TestParseSchedule_DST(t *testing.T) {
+	loc, err := time.LoadLocation("America/New_York")
+	isNil(err, t)
+
+	// 2026-03-08 02:00 EST 跳到 03:00 EDT, 当天没有 02:30
+	s, err := parseSchedule("30 2 * * *")
+	isNil(err, t)
+	got := s.next(time.Date(2026, 3, 7, 12, 0, 0, 0, loc))
+	equals(time.Date(2026, 3, 8, 3, 0, 0, 0, loc), got, t)
+	equals(time.Date(2026, 3, 9, 2, 30, 0, 0, loc), s.next(got), t)
+
+	// 每小时的计划不受影响, 01:59 之后的下一个整点是 03:00 EDT
+	s, err = parseSchedule("@hourly")
+	isNil(err, t)
+	equals(time.Date(2026, 3, 8, 3, 0, 0, 0, loc), s.next(time.Date(2026, 3, 8, 1, 59, 0, 0, loc)), t)
+
+	// 2026-11-01 02:00 EDT 回到 01:00 EST, 01:30 出现两次
+	s, err = parseSchedule("30 1 * * *")
+	isNil(err, t)
+	got = s.next(time.Date(2026, 10, 31, 12, 0, 0, 0, loc))
+	equals(time.Date(2026, 11, 1, 5, 30, 0, 0, time.UTC), got.UTC(), t) // 01:30 EDT
+	equals(time.Date(2026, 11, 2, 1, 30, 0, 0, loc), s.next(got), t)
+
+	// 每小时的计划在重复的时段内照常轮转
+	s, err = parseSchedule("@hourly")
+	isNil(err, t)
+	firstOne := time.Date(2026, 11, 1, 5, 0, 0, 0, time.UTC)   // 01:00 EDT
+	equals(firstOne.Add(time.Hour), s.next(firstOne).UTC(), t) // 01:00 EST
+}
+
+// TestParseSchedule_Invalid 测试无效的轮转计划返回错误
+func TestParseSchedule_Invalid(t *testing.T) {
+	invalid := []string{
+		"0 4 * *",      // 字段数量不足
+		"60 * * * *",   // 分钟超出范围
+		"0 24 * * *",   // 小时超出范围
+		"0 0 0 * *",    // 日期超出范围
+		"0 0 * 13 *",   // 月份超出范围
+		"0 0 * * 8",    // 周超出范围
+		"*/0 * * * *",  // 步长为 0
+		"5-1 * * * *",  // 范围倒置
+		"a * * * *",    // 非数字
+		"500ms",        // 间隔过短
+		"@fortnightly", // 未知描述符
+		"7h",           // 间隔不能整除 24h
+		"0 0 31 2 *",   // 2 月没有 31 日
+		"0 0 30,31 2 *",
+	}
+	for _, spec := range invalid {
+		if _, err := parseSchedule(spec); err == nil {
+			t.Errorf("期望 %q 解析失败", spec)
+		}
+	}
+
+	s, err := parseSchedule("  ")
+	isNil(err, t)
+	isNil(s, t)
+}
+
+// TestRotateSchedule_Hourly 测试按小时轮转: 同一小时内不轮转, 跨过整点后的首次写入触发轮转
+func TestRotateSchedule_Hourly(t *testing.T) {
+	originalCurrentTime := currentTime
+	originalFake := fakeCurrentTime
+	defer func() {
+		currentTime = originalCurrentTime
+		fakeCurrentTime = originalFake
+	}()
+	currentTime = fakeTime
+	fakeCurrentTime = time.Date(2026, 10, 16, 9, 10, 0, 0, time.UTC)
+
+	dir := makeTempDir("TestRotateSchedule_Hourly", t)
+	defer func() { _ = os.RemoveAll(dir) }()
+
+	l := &LogRotateX{LogFilePath: logFile(dir), RotateSchedule: "@hourly"}
+	defer func() { _ = l.Close() }()
+
+	_, err := l.Write([]byte("09:10\n"))
+	isNil(err, t)
+
+	// 同一小时内不轮转
+	fakeCurrentTime = time.Date(2026, 10, 16, 9, 59, 59, 0, time.UTC)
+	_, err = l.Write([]byte("09:59\n"))
+	isNil(err, t)
+	fileCount(dir, 1, t)
+
+	// 跨过整点后轮转
+	fakeCurrentTime = time.Date(2026, 10, 16, 10, 0, 1, 0, time.UTC)
+	_, err = l.Write([]byte("10:00\n"))
+	isNil(err, t)
+	existsWithContent(backupFile(dir), []byte("09:10\n09:59\n"), t)
+	existsWithContent(logFile(dir), []byte("10:00\n"), t)
+
+	// 边界只在跨过时重新计算
+	equals(time.Date(2026, 10, 16, 11, 0, 0, 0, time.UTC), l.nextRotation, t)
+}
+
+// TestRotateSchedule_InvalidSpec 测试无效的轮转计划在写入时返回错误
+func TestRotateSchedule_InvalidSpec(t *testing.T) {
+	dir := makeTempDir("TestRotateSchedule_InvalidSpec", t)
+	defer func() { _ = os.RemoveAll(dir) }()
+
+	l := &LogRotateX{LogFilePath: logFile(dir), RotateSchedule: "not a schedule"}
+	defer func() { _ = l.Close() }()
+
+	if _, err := l.Write([]byte("data")); err == nil {
+		t.Fatal("期望无效的轮转计划返回错误")
+	}
+}
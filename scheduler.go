@@ -0,0 +1,155 @@
+// scheduler.go 实现了logrotatex包的后台调度功能。
+// 后台协程在下一个轮转边界 (跨天或定时计划) 和定时清理间隔到期时唤醒，
+// 使没有写入的空闲服务也能按时轮转日志并执行保留规则。
+
+package logrotatex
+
+import (
+	"fmt"
+	"time"
+)
+
+// runScheduler 后台调度循环, 在 Close 时通过 stopCh 退出
+func (l *LogRotateX) runScheduler() {
+	defer func() {
+		// panic 保护, 防止后台协程崩溃导致程序退出
+		if r := recover(); r != nil {
+			l.reportError(OpRotate, l.filename(), fmt.Errorf("panic in background scheduler: %v", r))
+		}
+	}()
+
+	// 定时清理
+	var cleanupC <-chan time.Time
+	if l.CleanupInterval > 0 {
+		ticker := time.NewTicker(l.CleanupInterval)
+		defer ticker.Stop()
+		cleanupC = ticker.C
+	}
+
+	for {
+		// 定时轮转: 每轮重新计算下一个边界 (轮转或写入可能已推进边界)
+		var rotateC <-chan time.Time
+		var timer *time.Timer
+		if l.BackgroundRotate {
+			if next := l.nextBoundary(); !next.IsZero() {
+				timer = time.NewTimer(next.Sub(currentTime()))
+				rotateC = timer.C
+			}
+		}
+
+		select {
+		case <-l.stopCh:
+			if timer != nil {
+				timer.Stop()
+			}
+			return
+		case <-rotateC:
+			l.timedRotate()
+		case <-cleanupC:
+			l.timedCleanup()
+		}
+
+		if timer != nil {
+			timer.Stop()
+		}
+	}
+}
+
+// nextBoundary 返回下一个轮转边界 (跨天与定时计划中较早的一个)
+//
+// 返回值:
+//   - time.Time: 下一个轮转边界, 未启用任何定时轮转时返回零值
+func (l *LogRotateX) nextBoundary() time.Time {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.now()
+	var next time.Time
+
+	// 按天轮转: 下一个零点
+	if l.RotateByDay {
+		year, month, day := now.Date()
+		next = time.Date(year, month, day+1, 0, 0, 0, 0, now.Location())
+	}
+
+	// 定时计划: 复用已计算的边界, 与写入路径保持一致
+	if l.schedule != nil {
+		if l.nextRotation.IsZero() {
+			l.nextRotation = l.schedule.next(now)
+		}
+		if n := l.nextRotation; !n.IsZero() && (next.IsZero() || n.Before(next)) {
+			next = n
+		}
+	}
+
+	return next
+}
+
+// timedRotate 到达轮转边界时由后台协程调用, 与写入路径使用相同的边界判断
+func (l *LogRotateX) timedRotate() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.closed.Load() {
+		return
+	}
+
+	// 多进程模式: 持有轮转锁, 其他进程已轮转时 syncWithDisk 会关闭当前句柄
+	unlock, err := l.lockRotate()
+	if err != nil {
+		l.reportError(OpRotate, l.filename(), err)
+		return
+	}
+	defer unlock()
+	if l.MultiProcess {
+		if err := l.syncWithDisk(); err != nil {
+			l.reportError(OpRotate, l.filename(), err)
+			return
+		}
+	}
+
+	// 文件未打开 (如重启后尚未写入): 不记录按天轮转的日期, 首次写入时仍按已有文件的修改时间判断是否轮转;
+	// 只推进定时计划的边界, 避免调度协程立即再次唤醒
+	if l.file == nil {
+		if l.schedule != nil {
+			l.shouldRotateBySchedule()
+		}
+		return
+	}
+
+	// 未到达边界 (可能已被写入路径处理)
+	if !l.timeRotationDue() {
+		return
+	}
+
+	// 文件为空时不生成空的备份文件
+	if l.size == 0 {
+		return
+	}
+
+	if err := l.rotate(RotateReasonTime); err != nil {
+		l.reportError(OpRotate, l.filename(), err)
+	}
+}
+
+// timedCleanup 清理间隔到期时由后台协程调用, 按 Async 配置选择同步或异步清理
+func (l *LogRotateX) timedCleanup() {
+	if l.closed.Load() {
+		return
+	}
+
+	// 在锁内读取配置, 避免与 ApplyConfig 竞争
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	// 异步: 复用单协程清理循环
+	if l.Async {
+		l.cleanupAsync()
+		return
+	}
+
+	// 同步: 直接执行清理
+	if err := l.cleanupSync(); err != nil {
+		l.reportError(OpCleanup, l.dir(), err)
+	}
+}
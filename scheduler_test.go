@@ -0,0 +1,207 @@
+// scheduler_test.go 包含了后台调度 (BackgroundRotate/CleanupInterval) 的测试用例。
+// 该文件验证没有写入时后台协程仍会按边界轮转、按间隔清理，
+// 以及 Close 能够停止后台协程。
+
+package logrotatex
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// countBackups 统计目录中除当前日志文件外的 foobar 备份文件数量
+func countBackups(dir string) int {
+	entries, _ := os.ReadDir(dir)
+	n := 0
+	for _, e := range entries {
+		if strings.HasPrefix(e.Name(), "foobar_") {
+			n++
+		}
+	}
+	return n
+}
+
+// TestBackgroundRotate_Schedule 测试到达定时边界后无需写入即可轮转
+func TestBackgroundRotate_Schedule(t *testing.T) {
+	dir := makeTempDir("TestBackgroundRotate_Schedule", t)
+	defer func() { _ = os.RemoveAll(dir) }()
+
+	l := &LogRotateX{
+		LogFilePath:      logFile(dir),
+		RotateSchedule:   "1s",
+		BackgroundRotate: true,
+	}
+	defer func() { _ = l.Close() }()
+
+	_, err := l.Write([]byte("idle service\n"))
+	isNil(err, t)
+
+	// 之后不再写入, 等待后台协程轮转
+	waitUntil(t, 3*time.Second, 20*time.Millisecond, func() bool {
+		return countBackups(dir) == 1
+	}, "到达边界后后台协程未执行轮转")
+
+	// 当前文件为空, 后续边界不会生成空备份
+	time.Sleep(1500 * time.Millisecond)
+	equals(1, countBackups(dir), t)
+	existsWithContent(logFile(dir), []byte{}, t)
+}
+
+// TestBackgroundRotate_Midnight 测试按天轮转在零点由后台协程触发
+func TestBackgroundRotate_Midnight(t *testing.T) {
+	// 使用随真实时间流逝的模拟时钟, 从 23:59:59.5 开始
+	originalCurrentTime := currentTime
+	defer func() { currentTime = originalCurrentTime }()
+	offset := time.Until(time.Date(2026, 10, 16, 23, 59, 59, 500*int(time.Millisecond), time.UTC))
+	currentTime = func() time.Time { return time.Now().Add(offset) }
+
+	dir := makeTempDir("TestBackgroundRotate_Midnight", t)
+	defer func() { _ = os.RemoveAll(dir) }()
+
+	l := &LogRotateX{
+		LogFilePath:      logFile(dir),
+		RotateByDay:      true,
+		BackgroundRotate: true,
+	}
+	defer func() { _ = l.Close() }()
+
+	_, err := l.Write([]byte("yesterday\n"))
+	isNil(err, t)
+
+	waitUntil(t, 3*time.Second, 20*time.Millisecond, func() bool {
+		return countBackups(dir) == 1
+	}, "跨天后后台协程未执行轮转")
+
+	existsWithContent(filepath.Join(dir, "foobar_20261017000000.log"), []byte("yesterday\n"), t)
+}
+
+// TestBackgroundRotate_BeforeFirstWrite 测试重启后首次写入前后台协程到达边界,
+// 不影响首次写入按已有文件的修改时间轮转前一天的日志
+func TestBackgroundRotate_BeforeFirstWrite(t *testing.T) {
+	originalCurrentTime := currentTime
+	originalFake := fakeCurrentTime
+	defer func() {
+		currentTime = originalCurrentTime
+		fakeCurrentTime = originalFake
+	}()
+	currentTime = fakeTime
+	fakeCurrentTime = time.Date(2026, 10, 16, 0, 0, 1, 0, time.UTC)
+
+	dir := makeTempDir("TestBackgroundRotate_BeforeFirstWrite", t)
+	defer func() { _ = os.RemoveAll(dir) }()
+
+	// 模拟重启前一天写入的日志文件
+	yesterday := time.Date(2026, 10, 15, 23, 30, 0, 0, time.UTC)
+	isNil(os.WriteFile(logFile(dir), []byte("yesterday\n"), 0600), t)
+	isNil(os.Chtimes(logFile(dir), yesterday, yesterday), t)
+
+	l := &LogRotateX{LogFilePath: logFile(dir), RotateByDay: true, RotateSchedule: "1h"}
+	defer func() { _ = l.Close() }()
+
+	// 首次写入前到达边界
+	l.mu.Lock()
+	isNil(l.initDefaults(), t)
+	l.mu.Unlock()
+	l.timedRotate()
+	fileCount(dir, 1, t)
+
+	_, err := l.Write([]byte("today\n"))
+	isNil(err, t)
+	existsWithContent(filepath.Join(dir, "foobar_20261015233000.log"), []byte("yesterday\n"), t)
+	existsWithContent(logFile(dir), []byte("today\n"), t)
+}
+
+// TestCleanupInterval_Idle 测试定时清理在没有轮转时也会删除超出保留数量的旧文件
+func TestCleanupInterval_Idle(t *testing.T) {
+	for _, async := range []bool{false, true} {
+		dir := makeTempDir("TestCleanupInterval_Idle", t)
+
+		for _, name := range []string{
+			"foobar_20250101000000.log",
+			"foobar_20250102000000.log",
+			"foobar_20250103000000.log",
+		} {
+			isNil(os.WriteFile(filepath.Join(dir, name), []byte("old"), 0600), t)
+		}
+
+		l := &LogRotateX{
+			LogFilePath:     logFile(dir),
+			MaxFiles:        1,
+			Async:           async,
+			CleanupInterval: 50 * time.Millisecond,
+		}
+
+		// Sync 触发初始化并启动后台协程, 之后不再写入
+		isNil(l.Sync(), t)
+
+		waitUntil(t, 2*time.Second, 20*time.Millisecond, func() bool {
+			return countBackups(dir) == 1
+		}, "定时清理未删除超出保留数量的旧文件")
+		exists(filepath.Join(dir, "foobar_20250103000000.log"), t)
+
+		isNil(l.Close(), t)
+		_ = os.RemoveAll(dir)
+	}
+}
+
+// TestScheduler_StopsOnClose 测试 Close 会停止后台协程
+func TestScheduler_StopsOnClose(t *testing.T) {
+	dir := makeTempDir("TestScheduler_StopsOnClose", t)
+	defer func() { _ = os.RemoveAll(dir) }()
+
+	l := &LogRotateX{
+		LogFilePath:      logFile(dir),
+		RotateSchedule:   "1s",
+		BackgroundRotate: true,
+		CleanupInterval:  time.Hour,
+	}
+	_, err := l.Write([]byte("data\n"))
+	isNil(err, t)
+
+	done := make(chan struct{})
+	go func() {
+		_ = l.Close()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(2 * time.Second):
+		t.Fatal("Close 未能及时停止后台协程")
+	}
+
+	// 关闭后不再轮转
+	time.Sleep(1200 * time.Millisecond)
+	equals(0, countBackups(dir), t)
+}
+
+// TestScheduler_NotStartedAfterClose 测试关闭后的写入不会初始化并启动后台协程, 与 Close 并发时也能停止
+func TestScheduler_NotStartedAfterClose(t *testing.T) {
+	dir := makeTempDir("TestScheduler_NotStartedAfterClose", t)
+	defer func() { _ = os.RemoveAll(dir) }()
+
+	l := &LogRotateX{LogFilePath: logFile(dir), CleanupInterval: time.Hour}
+	isNil(l.Close(), t)
+	if _, err := l.Write([]byte("data\n")); err == nil {
+		t.Fatal("期望关闭后写入返回错误")
+	}
+	if l.stopCh != nil {
+		t.Fatal("关闭后不应启动后台协程")
+	}
+
+	// 首次写入与 Close 并发 (配合 -race 检查)
+	for i := 0; i < 20; i++ {
+		l := &LogRotateX{LogFilePath: logFile(dir), CleanupInterval: time.Hour}
+		done := make(chan struct{})
+		go func() {
+			_, _ = l.Write([]byte("data\n"))
+			close(done)
+		}()
+		isNil(l.Close(), t)
+		<-done
+		l.wg.Wait()
+	}
+}
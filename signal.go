@@ -0,0 +1,138 @@
+// signal.go 实现了logrotatex包的信号处理功能。
+// 该文件将操作系统信号映射为对一组 LogRotateX 或 BufferedWriter 的动作
+// (重新打开、轮转、同步)，便于与发送 SIGHUP 的传统 logrotate 配置配合使用。
+
+package logrotatex
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"sync"
+)
+
+// SignalAction 是收到信号后对目标执行的动作
+type SignalAction int
+
+const (
+	// SignalReopen 关闭并重新打开日志文件 (对应 LogRotateX.Reopen)
+	SignalReopen SignalAction = iota + 1
+
+	// SignalRotate 立即执行一次轮转 (对应 LogRotateX.Rotate)
+	SignalRotate
+
+	// SignalSync 将缓冲数据刷新并同步到磁盘 (对应 BufferedWriter.Flush 和 LogRotateX.Sync)
+	SignalSync
+)
+
+// String 返回动作的名称
+func (a SignalAction) String() string {
+	switch a {
+	case SignalReopen:
+		return "reopen"
+	case SignalRotate:
+		return "rotate"
+	case SignalSync:
+		return "sync"
+	default:
+		return fmt.Sprintf("SignalAction(%d)", int(a))
+	}
+}
+
+// HandleSignals 监听 actions 中的信号, 收到信号后按顺序对所有目标执行对应动作。
+//
+// 目标可以是 *LogRotateX、*BufferedWriter 或任何实现了 Reopen/Rotate/Sync 方法的写入器。
+// 对 *BufferedWriter 会先刷新缓冲区, 再对其底层写入器执行动作; 不支持该动作的目标会被跳过。
+//
+// 参数:
+//   - actions: 信号到动作的映射, 例如 {syscall.SIGHUP: SignalReopen}
+//   - targets: 需要执行动作的写入器
+//
+// 返回值:
+//   - func(): 停止监听的函数, 会等待处理协程退出, 可重复调用
+//
+// 示例:
+//
+//	stop := logrotatex.HandleSignals(map[os.Signal]logrotatex.SignalAction{
+//		syscall.SIGHUP:  logrotatex.SignalReopen,
+//		syscall.SIGUSR1: logrotatex.SignalRotate,
+//	}, logger, bufferedWriter)
+//	defer stop()
+func HandleSignals(actions map[os.Signal]SignalAction, targets ...io.Writer) func() {
+	// 复制映射, 避免调用方后续修改导致并发读写
+	table := make(map[os.Signal]SignalAction, len(actions))
+	sigs := make([]os.Signal, 0, len(actions))
+	for sig, action := range actions {
+		table[sig] = action
+		sigs = append(sigs, sig)
+	}
+
+	sigChan := make(chan os.Signal, 1)
+	done := make(chan struct{})
+	var wg sync.WaitGroup
+
+	// 未指定任何信号时不注册, 避免 signal.Notify 接收所有信号
+	if len(sigs) > 0 {
+		signal.Notify(sigChan, sigs...)
+	}
+
+	wg.Go(func() {
+		for {
+			select {
+			case sig := <-sigChan:
+				action := table[sig]
+				for _, target := range targets {
+					if err := applySignalAction(target, action); err != nil {
+						reportTargetError(target, OpSignal, fmt.Errorf("signal %v: %s failed: %w", sig, action, err))
+					}
+				}
+			case <-done:
+				return
+			}
+		}
+	})
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			signal.Stop(sigChan)
+			close(done)
+			wg.Wait()
+		})
+	}
+}
+
+// applySignalAction 对单个目标执行动作
+//
+// 参数:
+//   - target: 目标写入器
+//   - action: 需要执行的动作
+//
+// 返回值:
+//   - error: 执行失败时返回错误, 目标不支持该动作时返回 nil
+func applySignalAction(target io.Writer, action SignalAction) error {
+	// 带缓冲写入器: 先把缓冲数据写入当前文件, 再处理底层写入器
+	if bw, ok := target.(*BufferedWriter); ok {
+		if err := bw.Flush(); err != nil {
+			return err
+		}
+		return applySignalAction(bw.wc, action)
+	}
+
+	switch action {
+	case SignalReopen:
+		if r, ok := target.(interface{ Reopen() error }); ok {
+			return r.Reopen()
+		}
+	case SignalRotate:
+		if r, ok := target.(interface{ Rotate() error }); ok {
+			return r.Rotate()
+		}
+	case SignalSync:
+		if s, ok := target.(interface{ Sync() error }); ok {
+			return s.Sync()
+		}
+	}
+	return nil
+}
@@ -0,0 +1,103 @@
+// signal_test.go 包含了信号处理 (HandleSignals) 的测试用例。
+// 该文件通过向测试进程自身发送信号，验证信号到重新打开、轮转、同步动作的映射。
+//go:build linux || darwin
+// +build linux darwin
+
+package logrotatex
+
+import (
+	"os"
+	"path/filepath"
+	"syscall"
+	"testing"
+	"time"
+)
+
+// TestHandleSignals_Reopen 测试收到 SIGHUP 后重新打开被外部移走的日志文件
+func TestHandleSignals_Reopen(t *testing.T) {
+	dir := makeTempDir("TestHandleSignals_Reopen", t)
+	defer func() { _ = os.RemoveAll(dir) }()
+
+	l := &LogRotateX{LogFilePath: logFile(dir)}
+	defer func() { _ = l.Close() }()
+
+	stop := HandleSignals(map[os.Signal]SignalAction{syscall.SIGHUP: SignalReopen}, l)
+	defer stop()
+
+	_, err := l.Write([]byte("before\n"))
+	isNil(err, t)
+
+	// 模拟 logrotate: 移走文件后发送 SIGHUP
+	moved := filepath.Join(dir, "foobar.log.1")
+	isNil(os.Rename(logFile(dir), moved), t)
+	isNil(syscall.Kill(os.Getpid(), syscall.SIGHUP), t)
+
+	// 等待信号处理完成: 重新打开后日志路径上会出现新文件
+	waitUntil(t, 2*time.Second, 10*time.Millisecond, func() bool {
+		_, err := os.Stat(logFile(dir))
+		return err == nil
+	}, "收到 SIGHUP 后未重新打开日志文件")
+
+	_, err = l.Write([]byte("after\n"))
+	isNil(err, t)
+	existsWithContent(moved, []byte("before\n"), t)
+	existsWithContent(logFile(dir), []byte("after\n"), t)
+}
+
+// TestHandleSignals_Rotate 测试收到 SIGUSR1 后对多个目标执行轮转
+func TestHandleSignals_Rotate(t *testing.T) {
+	dir := makeTempDir("TestHandleSignals_Rotate", t)
+	defer func() { _ = os.RemoveAll(dir) }()
+
+	l1 := &LogRotateX{LogFilePath: filepath.Join(dir, "a.log")}
+	l2 := &LogRotateX{LogFilePath: filepath.Join(dir, "b.log")}
+	defer func() { _ = l1.Close() }()
+	defer func() { _ = l2.Close() }()
+
+	stop := HandleSignals(map[os.Signal]SignalAction{syscall.SIGUSR1: SignalRotate}, l1, l2)
+	defer stop()
+
+	_, err := l1.Write([]byte("a"))
+	isNil(err, t)
+	_, err = l2.Write([]byte("b"))
+	isNil(err, t)
+
+	isNil(syscall.Kill(os.Getpid(), syscall.SIGUSR1), t)
+
+	// 每个目标轮转后各有一个备份文件
+	waitUntil(t, 2*time.Second, 10*time.Millisecond, func() bool {
+		entries, _ := os.ReadDir(dir)
+		return len(entries) == 4
+	}, "收到 SIGUSR1 后未对所有目标执行轮转")
+}
+
+// TestHandleSignals_SyncBufferedWriter 测试收到信号后刷新 BufferedWriter 的缓冲区
+func TestHandleSignals_SyncBufferedWriter(t *testing.T) {
+	dir := makeTempDir("TestHandleSignals_SyncBufferedWriter", t)
+	defer func() { _ = os.RemoveAll(dir) }()
+
+	l := &LogRotateX{LogFilePath: logFile(dir)}
+	bw := NewBufferedWriter(l, &BufCfg{MaxBufferSize: 1024 * 1024, FlushInterval: time.Hour})
+	defer func() { _ = bw.Close() }()
+
+	stop := HandleSignals(map[os.Signal]SignalAction{syscall.SIGUSR2: SignalSync}, bw)
+	defer stop()
+
+	_, err := bw.Write([]byte("buffered\n"))
+	isNil(err, t)
+	equals(9, bw.BufferSize(), t)
+
+	isNil(syscall.Kill(os.Getpid(), syscall.SIGUSR2), t)
+
+	waitUntil(t, 2*time.Second, 10*time.Millisecond, func() bool {
+		return bw.BufferSize() == 0
+	}, "收到 SIGUSR2 后缓冲区未被刷新")
+	existsWithContent(logFile(dir), []byte("buffered\n"), t)
+}
+
+// TestHandleSignals_StopIdempotent 测试停止函数可以重复调用
+func TestHandleSignals_StopIdempotent(t *testing.T) {
+	stop := HandleSignals(map[os.Signal]SignalAction{syscall.SIGUSR1: SignalRotate})
+	stop()
+	stop()
+}
@@ -0,0 +1,15 @@
+// statfs_other.go 为不支持空间查询的平台提供占位实现。
+// 在这些平台上设置 MinFreeSpace 或 MinFreePercent 时，清理会返回 errors.ErrUnsupported。
+//go:build !linux && !darwin && !freebsd && !dragonfly && !windows
+// +build !linux,!darwin,!freebsd,!dragonfly,!windows
+
+package logrotatex
+
+import (
+	"errors"
+)
+
+// diskUsage 在当前平台不受支持。
+func diskUsage(_ string) (diskStats, error) {
+	return diskStats{}, errors.ErrUnsupported
+}
@@ -0,0 +1,23 @@
+// statfs_unix.go 实现了类Unix系统下的文件系统空间查询。
+// 该文件通过 statfs 系统调用获取日志目录所在文件系统的总容量和可用空间。
+//go:build linux || darwin || freebsd || dragonfly
+// +build linux darwin freebsd dragonfly
+
+package logrotatex
+
+import (
+	"syscall"
+)
+
+// diskUsage 返回 path 所在文件系统的空间信息, 可用空间不包括只有特权用户可用的保留块。
+func diskUsage(path string) (diskStats, error) {
+	var st syscall.Statfs_t
+	if err := syscall.Statfs(path, &st); err != nil {
+		return diskStats{}, err
+	}
+	bsize := uint64(st.Bsize)
+	return diskStats{
+		total: uint64(st.Blocks) * bsize,
+		avail: uint64(st.Bavail) * bsize,
+	}, nil
+}
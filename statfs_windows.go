@@ -0,0 +1,33 @@
+// statfs_windows.go 实现了Windows系统下的文件系统空间查询。
+// 该文件通过 kernel32 的 GetDiskFreeSpaceExW 获取日志目录所在卷的总容量和可用空间。
+//go:build windows
+// +build windows
+
+package logrotatex
+
+import (
+	"syscall"
+	"unsafe"
+)
+
+var procGetDiskFreeSpaceExW = modkernel32.NewProc("GetDiskFreeSpaceExW")
+
+// diskUsage 返回 path 所在卷的空间信息, 可用空间为当前用户可用的空间 (考虑磁盘配额)。
+func diskUsage(path string) (diskStats, error) {
+	p, err := syscall.UTF16PtrFromString(path)
+	if err != nil {
+		return diskStats{}, err
+	}
+
+	var avail, total, free uint64
+	r1, _, err := procGetDiskFreeSpaceExW.Call(
+		uintptr(unsafe.Pointer(p)),
+		uintptr(unsafe.Pointer(&avail)),
+		uintptr(unsafe.Pointer(&total)),
+		uintptr(unsafe.Pointer(&free)),
+	)
+	if r1 == 0 {
+		return diskStats{}, err
+	}
+	return diskStats{total: total, avail: avail}, nil
+}
@@ -0,0 +1,146 @@
+// stats.go 实现了logrotatex包的运行时统计功能。
+// 写入、轮转、压缩、删除和清理的计数器使用原子操作维护，写入路径只增加几次原子加法，
+// Stats 返回某一时刻的统计快照。
+
+package logrotatex
+
+import (
+	"sync/atomic"
+	"time"
+)
+
+// RotateReason 是触发轮转的原因
+type RotateReason string
+
+const (
+	// RotateReasonSize 写入后文件大小达到 MaxSize (或 MaxBytes)
+	RotateReasonSize RotateReason = "size"
+
+	// RotateReasonTime 跨天 (RotateByDay)、到达定时轮转边界 (RotateSchedule) 或文件名模式切换到新文件
+	RotateReasonTime RotateReason = "time"
+
+	// RotateReasonManual 调用 Rotate、RotateContext 或收到轮转信号
+	RotateReasonManual RotateReason = "manual"
+)
+
+// Stats 是 LogRotateX 的运行时统计快照, 计数器从创建实例开始累计
+type Stats struct {
+	Writes        int64 // Writes 是成功的 Write 调用次数
+	BytesWritten  int64 // BytesWritten 是成功写入的字节数
+	WriteErrors   int64 // WriteErrors 是返回错误的 Write 调用次数
+	DroppedWrites int64 // DroppedWrites 是因磁盘已满被丢弃的写入次数 (DiskFullPolicyDrop)
+	DroppedEvents int64 // DroppedEvents 是因事件通道已满被丢弃的事件数量
+
+	Rotations       int64 // Rotations 是轮转总次数
+	SizeRotations   int64 // SizeRotations 是按大小触发的轮转次数
+	TimeRotations   int64 // TimeRotations 是按天、定时计划或文件名模式触发的轮转次数
+	ManualRotations int64 // ManualRotations 是手动 (Rotate 或信号) 触发的轮转次数
+
+	Compressions     int64         // Compressions 是成功压缩的备份文件数量
+	CompressDuration time.Duration // CompressDuration 是压缩累计耗时
+	CompressedIn     int64         // CompressedIn 是压缩前的累计字节数
+	CompressedOut    int64         // CompressedOut 是压缩后的累计字节数
+	CompressRatio    float64       // CompressRatio 是累计压缩率 (CompressedOut / CompressedIn), 未压缩过时为 0
+
+	Removals      int64 // Removals 是清理删除的备份文件数量
+	CleanupRuns   int64 // CleanupRuns 是执行清理的轮数 (同步和异步)
+	CleanupReruns int64 // CleanupReruns 是异步清理期间再次触发而续跑的轮数
+
+	CleanupDuration time.Duration // CleanupDuration 是清理累计耗时 (包括压缩、删除和配额检查)
+
+	CurrentSize int64     // CurrentSize 是当前日志文件的大小
+	OpenedAt    time.Time // OpenedAt 是当前日志文件的打开时间, 未打开时为零值
+}
+
+// statsCounters 是运行时统计的计数器, 全部使用原子操作
+type statsCounters struct {
+	writes          atomic.Int64
+	bytesWritten    atomic.Int64
+	writeErrors     atomic.Int64
+	sizeRotations   atomic.Int64
+	timeRotations   atomic.Int64
+	manualRotations atomic.Int64
+	compressions    atomic.Int64
+	compressNanos   atomic.Int64
+	compressedIn    atomic.Int64
+	compressedOut   atomic.Int64
+	removals        atomic.Int64
+	cleanupRuns     atomic.Int64
+	cleanupReruns   atomic.Int64
+	cleanupNanos    atomic.Int64
+}
+
+// recordWrite 记录一次 Write 调用的结果
+func (c *statsCounters) recordWrite(n int, err error) {
+	if err != nil {
+		c.writeErrors.Add(1)
+		return
+	}
+	c.writes.Add(1)
+	c.bytesWritten.Add(int64(n))
+}
+
+// recordRotation 记录一次轮转
+func (c *statsCounters) recordRotation(reason RotateReason) {
+	switch reason {
+	case RotateReasonSize:
+		c.sizeRotations.Add(1)
+	case RotateReasonTime:
+		c.timeRotations.Add(1)
+	case RotateReasonManual:
+		c.manualRotations.Add(1)
+	}
+}
+
+// recordCompression 记录一次成功的压缩
+func (c *statsCounters) recordCompression(d time.Duration, in, out int64) {
+	c.compressions.Add(1)
+	c.compressNanos.Add(int64(d))
+	c.compressedIn.Add(in)
+	c.compressedOut.Add(out)
+}
+
+// recordCleanup 记录一轮清理的耗时, start 为该轮开始的时间
+func (c *statsCounters) recordCleanup(start time.Time) {
+	c.cleanupNanos.Add(int64(currentTime().Sub(start)))
+}
+
+// Stats 返回运行时统计快照。
+// 计数器以原子操作读取; 当前文件的大小和打开时间在内部锁下读取, 轮转期间调用会短暂等待。
+//
+// 返回值:
+//   - Stats: 统计快照
+func (l *LogRotateX) Stats() Stats {
+	c := &l.stats
+	s := Stats{
+		Writes:           c.writes.Load(),
+		BytesWritten:     c.bytesWritten.Load(),
+		WriteErrors:      c.writeErrors.Load(),
+		DroppedWrites:    l.droppedWrites.Load(),
+		DroppedEvents:    l.droppedEvents.Load(),
+		SizeRotations:    c.sizeRotations.Load(),
+		TimeRotations:    c.timeRotations.Load(),
+		ManualRotations:  c.manualRotations.Load(),
+		Compressions:     c.compressions.Load(),
+		CompressDuration: time.Duration(c.compressNanos.Load()),
+		CompressedIn:     c.compressedIn.Load(),
+		CompressedOut:    c.compressedOut.Load(),
+		Removals:         c.removals.Load(),
+		CleanupRuns:      c.cleanupRuns.Load(),
+		CleanupReruns:    c.cleanupReruns.Load(),
+		CleanupDuration:  time.Duration(c.cleanupNanos.Load()),
+	}
+	s.Rotations = s.SizeRotations + s.TimeRotations + s.ManualRotations
+	if s.CompressedIn > 0 {
+		s.CompressRatio = float64(s.CompressedOut) / float64(s.CompressedIn)
+	}
+
+	l.mu.Lock()
+	if l.file != nil {
+		s.CurrentSize = l.size
+		s.OpenedAt = l.openedAt
+	}
+	l.mu.Unlock()
+
+	return s
+}
@@ -0,0 +1,79 @@
+// stats_test.go 包含了运行时统计 (Stats) 的测试用例。
+// 该文件验证写入、按原因分类的轮转、压缩、删除、清理计数器，
+// 以及当前文件的大小和打开时间。
+
+package logrotatex
+
+import (
+	"errors"
+	"os"
+	"testing"
+	"time"
+)
+
+// TestStats 测试各项计数器随写入、轮转和清理累计
+func TestStats(t *testing.T) {
+	originalCurrentTime := currentTime
+	originalFake := fakeCurrentTime
+	defer func() {
+		currentTime = originalCurrentTime
+		fakeCurrentTime = originalFake
+	}()
+	currentTime = fakeTime
+	fakeCurrentTime = time.Date(2026, 10, 16, 9, 0, 0, 0, time.UTC)
+
+	dir := makeTempDir("TestStats", t)
+	defer func() { _ = os.RemoveAll(dir) }()
+
+	l := &LogRotateX{
+		LogFilePath:  logFile(dir),
+		MaxBytes:     10,
+		MaxFiles:     1,
+		Compress:     true,
+		CompressType: ".zip",
+	}
+	defer func() { _ = l.Close() }()
+
+	equals(Stats{}, l.Stats(), t)
+
+	// 两次写入, 第二次触发按大小轮转
+	_, err := l.Write([]byte("012345678"))
+	isNil(err, t)
+	fakeCurrentTime = fakeCurrentTime.Add(time.Minute)
+	_, err = l.Write([]byte("abc"))
+	isNil(err, t)
+
+	// 手动轮转, 最旧的压缩文件超出 MaxFiles 被删除
+	fakeCurrentTime = fakeCurrentTime.Add(time.Minute)
+	isNil(l.Rotate(), t)
+	_, err = l.Write([]byte("tail"))
+	isNil(err, t)
+
+	// 写入失败计入错误
+	original := writeFile
+	writeFile = func(*os.File, []byte) (int, error) { return 0, errors.New("boom") }
+	_, err = l.Write([]byte("lost"))
+	writeFile = original
+	if err == nil {
+		t.Fatal("期望写入失败")
+	}
+
+	s := l.Stats()
+	equals(int64(3), s.Writes, t)
+	equals(int64(16), s.BytesWritten, t)
+	equals(int64(1), s.WriteErrors, t)
+	equals(int64(2), s.Rotations, t)
+	equals(int64(1), s.SizeRotations, t)
+	equals(int64(0), s.TimeRotations, t)
+	equals(int64(1), s.ManualRotations, t)
+	equals(int64(2), s.Compressions, t)
+	equals(int64(12), s.CompressedIn, t)
+	if s.CompressedOut <= 0 || s.CompressRatio <= 0 {
+		t.Fatalf("期望记录压缩后的大小, 实际: %d, %f", s.CompressedOut, s.CompressRatio)
+	}
+	equals(int64(1), s.Removals, t)
+	equals(int64(2), s.CleanupRuns, t)
+	equals(int64(4), s.CurrentSize, t)
+	equals(fakeCurrentTime, s.OpenedAt, t)
+	fileCount(dir, 2, t)
+}
@@ -0,0 +1,133 @@
+// validate.go 实现了logrotatex包的配置校验功能。
+// initDefaults 会把无效值静默修正为默认值 (如负数的 MaxAge 变为 0)，Validate 在使用前报告这些问题，
+// 以及未知的压缩类型、轮转方式和无效的字段组合，所有问题合并为一个 ConfigErrors 返回。
+
+package logrotatex
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+)
+
+// errNegative 是负数配置项的错误原因
+var errNegative = errors.New("must not be negative")
+
+// Validate 检查配置是否有效, 不修改任何字段。
+// LoadConfig 和 FromConfig 会自动调用; 直接构造实例时可以在第一次写入前调用。
+//
+// 返回值:
+//   - error: 配置无效时返回 ConfigErrors, 包含所有发现的问题; 配置有效时返回 nil
+func (l *LogRotateX) Validate() error {
+	return l.validate().errOrNil()
+}
+
+// validate 检查配置并返回所有问题
+func (l *LogRotateX) validate() ConfigErrors {
+	var errs ConfigErrors
+	add := func(field string, value any, err error) {
+		errs = append(errs, &FieldError{Field: field, Value: fmt.Sprint(value), Err: err})
+	}
+
+	// 不能为负数的字段
+	for _, f := range []struct {
+		name     string
+		value    any
+		negative bool
+	}{
+		{"MaxSize", l.MaxSize, l.MaxSize < 0},
+		{"MaxBytes", l.MaxBytes, l.MaxBytes < 0},
+		{"MaxTotalSize", l.MaxTotalSize, l.MaxTotalSize < 0},
+		{"MinFreeSpace", l.MinFreeSpace, l.MinFreeSpace < 0},
+		{"FreeSpaceCheckInterval", l.FreeSpaceCheckInterval, l.FreeSpaceCheckInterval < 0},
+		{"MaxAge", l.MaxAge, l.MaxAge < 0},
+		{"MaxFiles", l.MaxFiles, l.MaxFiles < 0},
+		{"CleanupInterval", l.CleanupInterval, l.CleanupInterval < 0},
+		{"WatchInterval", l.WatchInterval, l.WatchInterval < 0},
+	} {
+		if f.negative {
+			add(f.name, f.value, errNegative)
+		}
+	}
+
+	if l.MinFreePercent < 0 || l.MinFreePercent >= 100 {
+		add("MinFreePercent", l.MinFreePercent, errors.New("must be between 0 and 100"))
+	}
+
+	// 日志文件路径模式和定时轮转计划
+	if hasPattern(l.LogFilePath) {
+		if _, err := parsePattern(filepath.Clean(l.LogFilePath)); err != nil {
+			add("LogFilePath", l.LogFilePath, err)
+		}
+	}
+	if _, err := parseSchedule(l.RotateSchedule); err != nil {
+		add("RotateSchedule", l.RotateSchedule, err)
+	}
+
+	// 枚举类型的字段, 空值表示使用默认值
+	if l.CompressType != "" {
+		if t, err := ParseCompressType(l.CompressType.String()); err != nil {
+			add("CompressType", l.CompressType, err)
+		} else if t != l.CompressType {
+			add("CompressType", l.CompressType, fmt.Errorf("must be written as %q", t))
+		}
+	}
+	switch l.RotateMode {
+	case "", RotateModeRename, RotateModeCopyTruncate:
+	default:
+		add("RotateMode", l.RotateMode, fmt.Errorf("must be %q or %q", RotateModeRename, RotateModeCopyTruncate))
+	}
+	switch l.DiskFullPolicy {
+	case "", DiskFullPolicyFail, DiskFullPolicyEvict, DiskFullPolicyFallback, DiskFullPolicyDrop:
+	default:
+		add("DiskFullPolicy", l.DiskFullPolicy, fmt.Errorf("must be one of %q, %q, %q, %q",
+			DiskFullPolicyFail, DiskFullPolicyEvict, DiskFullPolicyFallback, DiskFullPolicyDrop))
+	}
+
+	// 字段组合
+	maxFile := int64(l.MaxBytes)
+	if maxFile <= 0 {
+		size := l.MaxSize
+		if size <= 0 {
+			size = defaultMaxSize
+		}
+		maxFile = int64(size) * int64(megabyte)
+	}
+	if l.MaxTotalSize > 0 && int64(l.MaxTotalSize) < maxFile {
+		add("MaxTotalSize", l.MaxTotalSize, fmt.Errorf("is smaller than the maximum file size %s", ByteSize(maxFile)))
+	}
+	if l.BackgroundRotate && !l.RotateByDay && l.RotateSchedule == "" {
+		add("BackgroundRotate", l.BackgroundRotate, errors.New("requires RotateByDay or RotateSchedule"))
+	}
+	if l.LinkName != "" && l.LogFilePath != "" && filepath.Clean(l.LinkName) == filepath.Clean(l.LogFilePath) {
+		add("LinkName", l.LinkName, errors.New("must differ from LogFilePath"))
+	}
+	if l.BackupLinkName != "" && l.LinkName != "" && filepath.Clean(l.BackupLinkName) == filepath.Clean(l.LinkName) {
+		add("BackupLinkName", l.BackupLinkName, errors.New("must differ from LinkName"))
+	}
+
+	return errs
+}
+
+// Validate 检查缓冲写入器配置是否有效, 不修改任何字段。
+//
+// 返回值:
+//   - error: 配置无效时返回 ConfigErrors; 配置有效时返回 nil
+func (c *BufCfg) Validate() error {
+	return c.validate().errOrNil()
+}
+
+// validate 检查缓冲写入器配置并返回所有问题
+func (c *BufCfg) validate() ConfigErrors {
+	var errs ConfigErrors
+	if c.MaxBufferSize < 0 {
+		errs = append(errs, &FieldError{Field: "BufCfg.MaxBufferSize", Value: fmt.Sprint(c.MaxBufferSize), Err: errNegative})
+	}
+	if c.FlushInterval < 0 {
+		errs = append(errs, &FieldError{Field: "BufCfg.FlushInterval", Value: c.FlushInterval.String(), Err: errNegative})
+	} else if c.FlushInterval > 0 && c.FlushInterval < MinFlushInterval {
+		errs = append(errs, &FieldError{Field: "BufCfg.FlushInterval", Value: c.FlushInterval.String(),
+			Err: fmt.Errorf("must be at least %s", MinFlushInterval)})
+	}
+	return errs
+}